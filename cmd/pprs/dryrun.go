@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/amberpixels/peppers/internal/habanero"
+	"github.com/jomei/notionapi"
+)
+
+// dryRunPage is a page of the tree printed in dry run as JSON
+type dryRunPage struct {
+	Path     string                       `json:"path,omitempty"`
+	Request  *notionapi.PageCreateRequest `json:"request"`
+	Subpages []*dryRunPage                `json:"subpages,omitempty"`
+}
+
+// printPage prints the converted page (see --output-format) instead of publishing it
+func printPage(ctx context.Context, publisher *habanero.Publisher, parent notionapi.Parent, page *habanero.Page) {
+	writeOutput(func(w io.Writer) error {
+		req, err := publisher.PageRequest(ctx, parent, page)
+		if err != nil {
+			return err
+		}
+
+		if in.OutputFormat == "tree" {
+			return habanero.DumpPage(w, &habanero.Page{Properties: req.Properties, Blocks: req.Children}, 0)
+		}
+		return writeJSON(w, req)
+	})
+}
+
+// printTree prints pages converted from the given Markdown files (relative to the root directory) instead of publishing them
+// Links between documents can't point at pages that don't exist yet, so they are resolved as links to other files
+func printTree(ctx context.Context, publisher *habanero.Publisher, root string, files []string) {
+	docs := newDocTree(root, files)
+	tree := &habanero.PageTree{}
+	if err := convertTree(ctx, root, docs, tree, nil); err != nil {
+		ExitWithError("Couldn't convert the directory", err)
+	}
+
+	writeOutput(func(w io.Writer) error {
+		if in.OutputFormat == "tree" {
+			return dumpTree(ctx, w, publisher, tree, 0)
+		}
+
+		parent := notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: notionapi.PageID(in.NotionParentID)}
+		pages, err := dryRunPages(ctx, publisher, parent, docs, tree)
+		if err != nil {
+			return err
+		}
+		return writeJSON(w, pages)
+	})
+}
+
+func dumpTree(ctx context.Context, w io.Writer, publisher *habanero.Publisher, tree *habanero.PageTree, depth int) error {
+	req, err := publisher.PageRequest(ctx, notionapi.Parent{}, tree.Page)
+	if err != nil {
+		return err
+	}
+	if err := habanero.DumpPage(w, &habanero.Page{Properties: req.Properties, Blocks: req.Children}, depth); err != nil {
+		return err
+	}
+	for _, child := range tree.Children {
+		if err := dumpTree(ctx, w, publisher, child, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dryRunPages(ctx context.Context, publisher *habanero.Publisher, parent notionapi.Parent, doc *docTree, tree *habanero.PageTree) (*dryRunPage, error) {
+	req, err := publisher.PageRequest(ctx, parent, tree.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dryRunPage{Path: doc.Path, Request: req}
+	for i, child := range doc.Children {
+		// Subpages are created under pages that don't exist yet
+		subpage, err := dryRunPages(ctx, publisher, notionapi.Parent{Type: notionapi.ParentTypePageID}, child, tree.Children[i])
+		if err != nil {
+			return nil, err
+		}
+		page.Subpages = append(page.Subpages, subpage)
+	}
+
+	return page, nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeOutput writes into the output file (see --output) or stdout
+func writeOutput(write func(w io.Writer) error) {
+	if in.Output == "" || in.Output == "-" {
+		if err := write(os.Stdout); err != nil {
+			ExitWithError("Couldn't print the converted page", err)
+		}
+		return
+	}
+
+	f, err := os.Create(in.Output)
+	if err != nil {
+		ExitWithError("Couldn't create the output file", err)
+	}
+	if err := write(f); err != nil {
+		_ = f.Close() //nolint:errcheck
+		ExitWithError("Couldn't write the converted page", err)
+	}
+	if err := f.Close(); err != nil {
+		ExitWithError("Couldn't write the converted page", err)
+	}
+}
@@ -1,28 +1,53 @@
 package main
 
 import (
+	"cmp"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
 
 	"github.com/alecthomas/kong"
-	"github.com/amberpixels/peppers/internal/jalapeno"
+	"github.com/amberpixels/peppers/internal/habanero"
+	"github.com/amberpixels/peppers/pkg/jalapeno"
 	"github.com/joho/godotenv"
 	"github.com/jomei/notionapi"
-	"github.com/yuin/goldmark"
-	"github.com/yuin/goldmark/extension"
-	"github.com/yuin/goldmark/parser"
 )
 
 var in struct {
-	NotionAPIToken string `help:"Notion API token." env:"NOTION_API_TOKEN"`
-	NotionParentID string `help:"Parent page ID in Notion." env:"NOTION_PARENT_PAGE_ID"`
-	FileName       string `help:"Path to the local README.md file." env:"FILE_NAME"`
+	NotionAPIToken string            `help:"Notion API token." env:"NOTION_API_TOKEN"`
+	NotionParentID string            `help:"Parent page ID in Notion." env:"NOTION_PARENT_PAGE_ID"`
+	DatabaseID     string            `help:"ID of the Notion database to publish into (instead of the parent page)." env:"NOTION_DATABASE_ID"`
+	Property       map[string]string `help:"Extra property of the page in the database (e.g. --property Repo=peppers)." env:"NOTION_PAGE_PROPERTIES"`
+	FileName       string            `help:"Path to the local README.md file, or a directory (or a glob pattern, e.g. 'docs/*.md') to publish as a tree of pages." env:"FILE_NAME"`
+	RepoURL        string            `help:"Browse URL of the Markdown file's directory in the repository (e.g. https://github.com/owner/repo/blob/main/docs). Relative links to other files point there." env:"REPO_URL"`
+	ImageBaseURL   *url.URL          `help:"Base URL local images are rewritten to, standing for the Markdown file's directory (e.g. raw GitHub URL). If omitted, local images are uploaded into Notion." env:"IMAGE_BASE_URL"`
+
+	FootnoteStyle       string `help:"How footnotes are rendered: section, callout or toggle." enum:"section,callout,toggle" default:"section" env:"FOOTNOTE_STYLE"`
+	HeadingStrategy     string `help:"How H1-H6 are mapped into Notion's three heading levels: clamp, shift or paragraph." enum:"clamp,shift,paragraph" default:"clamp" env:"HEADING_STRATEGY"`
+	ToggleHeadings      int    `help:"Make headings of the given level (1-6) toggleable with their sections nested inside (0 to disable)." default:"0" env:"TOGGLE_HEADINGS"`
+	DefinitionListStyle string `help:"How definition list terms are rendered: paragraph or toggle." enum:"paragraph,toggle" default:"paragraph" env:"DEFINITION_LIST_STYLE"`
+	HTMLStrategy        string `help:"How HTML blocks that can't be converted are rendered: text, code or skip." enum:"text,code,skip" default:"text" env:"HTML_STRATEGY"`
+	Strict              bool   `help:"Fail if any Markdown construct can't be converted without losses (otherwise they are reported as warnings)." env:"STRICT"`
+
+	DryRun       bool   `help:"Print the converted Notion payload instead of publishing it (same as the convert command)." env:"DRY_RUN"`
+	OutputFormat string `help:"Format of the converted payload printed by convert (or --dry-run): json (Notion API request) or tree (human-readable block tree)." enum:"json,tree" default:"json" env:"OUTPUT_FORMAT"`
+	Output       string `help:"File the converted payload is written into by convert (or --dry-run). Stdout by default." short:"o" default:"-" env:"OUTPUT"`
 
 	DevMode bool `help:"Dev mode (verbose logging, etc)" env:"DEV_MODE"`
+
+	Create struct{} `cmd:"" default:"1" help:"Create a new Notion page from the Markdown file (default)."`
+	Sync   struct {
+		NotionPageID string `help:"ID of the previously created Notion page. If omitted, it is taken from notion_page_id of the front matter or looked up by title under the parent page." env:"NOTION_PAGE_ID"`
+	} `cmd:"" help:"Update the Notion page previously created for the Markdown file (or create it if missing)."`
+	Pull struct {
+		NotionPageID string `help:"ID of the Notion page to pull. If omitted, it is taken from notion_page_id of the front matter or looked up by the title of the Markdown file under the parent page (or in the database)." env:"NOTION_PAGE_ID"`
+	} `cmd:"" help:"Fetch the Notion page and write it as Markdown into the Markdown file (or stdout if no file is given)."`
+	Convert struct{} `cmd:"" help:"Convert the Markdown file and print the Notion payload without calling Notion API (no token needed)."`
 }
 
 func main() {
@@ -34,8 +59,7 @@ func main() {
 		slog.Warn("failed to read .env: " + err.Error())
 	}
 
-	// for now we do not need result of Kong. It will be needed later, when we have commands
-	_ = kong.Parse(&in)
+	kongCtx := kong.Parse(&in)
 
 	// Create a context that is canceled when an interrupt or termination signal is received
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
@@ -45,52 +69,201 @@ func main() {
 		slog.SetLogLoggerLevel(slog.LevelDebug)
 	}
 
+	slog.Debug("Using Notion API with the given token: " + in.NotionAPIToken)
+
+	client := notionapi.NewClient(notionapi.Token(in.NotionAPIToken))
+
+	if kongCtx.Command() == "pull" {
+		pullPage(ctx, habanero.NewPublisher(client))
+		return
+	}
+
+	// A directory or a glob pattern is converted into a hierarchy of pages
+	info, statErr := os.Stat(in.FileName)
+	treeMode := (statErr == nil && info.IsDir()) || (statErr != nil && isGlob(in.FileName))
+
+	// Nothing is published in dry run, the payload is printed instead
+	dryRun := in.DryRun || kongCtx.Command() == "convert"
+
+	// Display the parsed parameters (keeping stdout clean for the printed payload)
+	status := os.Stdout
+	if dryRun {
+		status = os.Stderr
+	}
+	fmt.Fprintf(status, "Converting Markdown File [%s] into Notion [%s]\n", in.FileName, cmp.Or(in.DatabaseID, in.NotionParentID))
+
+	if treeMode {
+		root, files, err := collectDocs(in.FileName)
+		if err != nil {
+			ExitWithError("Couldn't find Markdown files", err)
+		}
+		if in.DatabaseID != "" {
+			ExitWithError("Couldn't publish the directory", errors.New("database mode supports single files only"))
+		}
+
+		publisher := newPublisher(client, root, dryRun)
+		if dryRun {
+			printTree(ctx, publisher, root, files)
+			return
+		}
+		publishTree(ctx, publisher, root, files, kongCtx.Command() == "sync")
+		return
+	}
+
 	source, err := os.ReadFile(in.FileName)
 	if err != nil {
 		ExitWithError("Couldn't read the source file", err)
 	}
 
-	// Display the parsed parameters
-	fmt.Printf("Converting Markdown File [%s] into Notion [%s]\n", in.FileName, in.NotionParentID)
+	result, page, err := convertDocument(ctx, newParser(filepath.Base(in.FileName), nil), source)
+	if err != nil {
+		ExitWithError("Couldn't parse the given file", err)
+	}
+	printDiagnostics(in.FileName, result.Diagnostics)
+	props := page.Properties
+
+	// Local images are resolved against the Markdown file's directory
+	publisher := newPublisher(client, filepath.Dir(in.FileName), dryRun)
+
+	parent := notionapi.Parent{
+		Type:   notionapi.ParentTypePageID,
+		PageID: notionapi.PageID(in.NotionParentID),
+	}
+
+	// Pages in a database get all the front matter (and given) properties, validated against the database schema
+	var schema notionapi.PropertyConfigs
+	if in.DatabaseID != "" {
+		parent = notionapi.Parent{
+			Type:       notionapi.ParentTypeDatabaseID,
+			DatabaseID: notionapi.DatabaseID(in.DatabaseID),
+		}
+
+		for name, prop := range result.FrontMatter.Properties() {
+			props[name] = prop
+		}
+		for name, value := range in.Property {
+			props[name] = notionapi.RichTextProperty{RichText: []notionapi.RichText{*notionapi.NewTextRichText(value)}}
+		}
+
+		// The schema can't be fetched in dry run, so properties are printed as they are
+		if !dryRun {
+			schema, err = publisher.DatabaseSchema(ctx, parent.DatabaseID)
+			if err != nil {
+				ExitWithError("failed to fetch the Notion database schema", err)
+			}
+
+			props, err = habanero.MatchSchema(schema, props)
+			if err != nil {
+				ExitWithError("Couldn't fill the Notion database properties", err)
+			}
+		}
+	}
+
+	page.Properties = props
+
+	if dryRun {
+		printPage(ctx, publisher, parent, page)
+		return
+	}
+
+	switch kongCtx.Command() {
+	case "sync":
+		pageID := notionapi.PageID(cmp.Or(in.Sync.NotionPageID, result.FrontMatter.PageID()))
+		if pageID == "" {
+			if in.DatabaseID != "" {
+				pageID, err = publisher.FindDatabasePage(ctx, parent.DatabaseID, schema, habanero.PageTitle(props))
+			} else {
+				pageID, err = publisher.FindChildPage(ctx, parent.PageID, habanero.PageTitle(props))
+			}
+			if errors.Is(err, habanero.ErrPageNotFound) {
+				// Pages are matched by titles, so a renamed document gets a new page (the old one is left as it is)
+				fmt.Fprintf(os.Stderr, "warning: no Notion page titled %q found, creating a new one. "+
+					"If the document was renamed, set notion_page_id in its front matter (or --notion-page-id) to keep updating the old page\n",
+					habanero.PageTitle(props))
+				createPage(ctx, publisher, parent, page)
+				return
+			} else if err != nil {
+				ExitWithError("failed to find the Notion page", err)
+			}
+		}
+
+		notionPageResult, err := publisher.Sync(ctx, pageID, page)
+		if err != nil {
+			ExitWithError("failed to sync the Notion page", err)
+		}
+
+		fmt.Printf("Successfully synced Notion page: %s\n", notionPageResult.URL)
+	default:
+		createPage(ctx, publisher, parent, page)
+	}
+}
+
+// newParser creates a parser for the document placed at the given path (relative to the converted directory)
+// Links to other documents are resolved into the given pages (if any) or the repository URL (if it's set)
+func newParser(docPath string, pages map[string]string, opts ...jalapeno.ParserOption) *jalapeno.Parser {
+	parserOpts := []jalapeno.ParserOption{
+		jalapeno.WithFootnoteStyle(jalapeno.FootnoteStyle(in.FootnoteStyle)),
+		jalapeno.WithDefinitionListStyle(jalapeno.DefinitionListStyle(in.DefinitionListStyle)),
+		jalapeno.WithHeadingStrategy(jalapeno.HeadingStrategy(in.HeadingStrategy)),
+		jalapeno.WithHTMLStrategy(jalapeno.HTMLStrategy(in.HTMLStrategy)),
+		jalapeno.WithToggleHeadings(in.ToggleHeadings),
+		jalapeno.WithStrictMode(in.Strict),
+	}
+	if in.RepoURL != "" || len(pages) > 0 {
+		parserOpts = append(parserOpts, jalapeno.WithLinkResolver(&jalapeno.RepoLinkResolver{
+			DocPath: docPath,
+			Pages:   pages,
+			RepoURL: in.RepoURL,
+		}))
+	}
 
-	p := jalapeno.NewParser(goldmark.New(
-		goldmark.WithExtensions(
-			extension.GFM,
-			extension.Table,
-			extension.TaskList,
-		),
-		goldmark.WithParserOptions(
-			parser.WithAutoHeadingID(),
-		),
-	))
+	return jalapeno.NewParser(nil, append(parserOpts, opts...)...)
+}
 
-	jalapeno.SetDebugSource(source)
-	blocks, err := p.ParseBlocks(source)
+// convertDocument converts the given Markdown document into a Notion page
+func convertDocument(ctx context.Context, p *jalapeno.Parser, source []byte) (*jalapeno.Result, *habanero.Page, error) {
+	result, err := p.Convert(ctx, source)
 	if err != nil {
-		ExitWithError("Couldn't parse the given file", err)
+		return nil, nil, err
 	}
 
-	blocks, props := jalapeno.PrepareNotionPageProperties(blocks)
+	return result, &habanero.Page{
+		Properties: result.Properties,
+		Icon:       result.Icon,
+		Cover:      result.Cover,
+		Blocks:     result.Blocks,
+	}, nil
+}
 
-	slog.Debug("Using Notion API with the given token: " + in.NotionAPIToken)
+// printDiagnostics prints constructs of the given Markdown file that couldn't be converted without losses
+func printDiagnostics(fileName string, diagnostics jalapeno.Diagnostics) {
+	for _, d := range diagnostics {
+		fmt.Fprintf(os.Stderr, "warning: %s:%s\n", fileName, d)
+	}
+}
 
-	pageReq := &notionapi.PageCreateRequest{
-		Parent: notionapi.Parent{
-			Type:   notionapi.ParentTypePageID,
-			PageID: notionapi.PageID(in.NotionParentID),
-		},
-		Properties: props,
-		Children:   blocks,
+func titleProperties(title []notionapi.RichText) notionapi.Properties {
+	return notionapi.Properties{
+		string(notionapi.PropertyConfigTypeTitle): notionapi.TitleProperty{Title: title},
 	}
-	_ = blocks
+}
 
-	// TEMPORARY for debugging. TODO: remove when done
-	jj, _ := json.Marshal(pageReq) //nolint:errcheck
-	fmt.Println(string(jj))
+// newPublisher creates a publisher resolving local images against the given directory
+// Local images are not uploaded in dry run
+func newPublisher(client *notionapi.Client, baseDir string, dryRun bool) *habanero.Publisher {
+	publisherOpts := []habanero.PublisherOption{habanero.WithBaseDir(baseDir)}
+	if in.ImageBaseURL != nil {
+		publisherOpts = append(publisherOpts, habanero.WithImageBaseURL(in.ImageBaseURL))
+	} else if !dryRun {
+		uploader := habanero.NewFileUploader(notionapi.Token(in.NotionAPIToken), habanero.NotionAPIURL)
+		publisherOpts = append(publisherOpts, habanero.WithFileUploader(uploader))
+	}
 
-	client := notionapi.NewClient(notionapi.Token(in.NotionAPIToken))
+	return habanero.NewPublisher(client, publisherOpts...)
+}
 
-	notionPageResult, err := client.Page.Create(ctx, pageReq)
+func createPage(ctx context.Context, publisher *habanero.Publisher, parent notionapi.Parent, page *habanero.Page) {
+	notionPageResult, err := publisher.Create(ctx, parent, page)
 	if err != nil {
 		ExitWithError("failed to create the Notion page", err)
 	}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/amberpixels/peppers/internal/habanero"
+	"github.com/amberpixels/peppers/internal/serrano"
+	"github.com/amberpixels/peppers/pkg/jalapeno"
+	"github.com/jomei/notionapi"
+)
+
+// pullPage fetches the Notion page and writes it as Markdown into the Markdown file (or stdout)
+// Front matter of the existing file is kept as it is
+func pullPage(ctx context.Context, publisher *habanero.Publisher) {
+	var source []byte
+	if in.FileName != "" && in.FileName != "-" {
+		var err error
+		source, err = os.ReadFile(in.FileName)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			ExitWithError("Couldn't read the Markdown file", err)
+		}
+	}
+
+	frontMatter, frontMatterBlock := existingFrontMatter(source)
+
+	pageID := notionapi.PageID(cmp.Or(in.Pull.NotionPageID, frontMatter.PageID()))
+	if pageID == "" {
+		if source == nil {
+			ExitWithError("Couldn't find the Notion page", errors.New("either page ID or an existing Markdown file is required"))
+		}
+		pageID = findPulledPage(ctx, publisher, source)
+	}
+
+	page, err := publisher.Fetch(ctx, pageID)
+	if err != nil {
+		ExitWithError("failed to fetch the Notion page", err)
+	}
+
+	// Title kept in the front matter is not duplicated as the H1 heading
+	title := habanero.PageTitle(page.Properties)
+	if frontMatter.Title() != nil {
+		title = ""
+	}
+	markdown := append(frontMatterBlock, serrano.RenderPage(title, page.Blocks, localImages(ctx, source))...)
+
+	if in.FileName == "" || in.FileName == "-" {
+		if _, err := os.Stdout.Write(markdown); err != nil {
+			ExitWithError("Couldn't print the Markdown", err)
+		}
+		return
+	}
+
+	if err := os.WriteFile(in.FileName, markdown, 0o644); err != nil { //nolint:gosec // Markdown files are public
+		ExitWithError("Couldn't write the Markdown file", err)
+	}
+	fmt.Printf("Successfully pulled Notion page into %s\n", in.FileName)
+}
+
+// findPulledPage looks up the page previously published from the given Markdown source by its title
+func findPulledPage(ctx context.Context, publisher *habanero.Publisher, source []byte) notionapi.PageID {
+	_, page, err := convertDocument(ctx, newParser(filepath.Base(in.FileName), nil), source)
+	if err != nil {
+		ExitWithError("Couldn't parse the Markdown file", err)
+	}
+	title := habanero.PageTitle(page.Properties)
+
+	var pageID notionapi.PageID
+	if in.DatabaseID != "" {
+		databaseID := notionapi.DatabaseID(in.DatabaseID)
+		schema, err := publisher.DatabaseSchema(ctx, databaseID)
+		if err != nil {
+			ExitWithError("failed to fetch the Notion database schema", err)
+		}
+		pageID, err = publisher.FindDatabasePage(ctx, databaseID, schema, title)
+		if err != nil {
+			ExitWithError("failed to find the Notion page", err)
+		}
+	} else {
+		pageID, err = publisher.FindChildPage(ctx, notionapi.PageID(in.NotionParentID), title)
+		if err != nil {
+			ExitWithError("failed to find the Notion page", err)
+		}
+	}
+
+	return pageID
+}
+
+// localImages returns paths of local images of the given Markdown source, so images uploaded from them keep the paths
+func localImages(ctx context.Context, source []byte) []string {
+	if source == nil {
+		return nil
+	}
+	_, page, err := convertDocument(ctx, newParser(filepath.Base(in.FileName), nil), source)
+	if err != nil {
+		return nil
+	}
+	return habanero.LocalImages(page.Blocks)
+}
+
+// existingFrontMatter returns the front matter of the given Markdown source and its raw block (with delimiters)
+func existingFrontMatter(source []byte) (jalapeno.FrontMatter, []byte) {
+	frontMatter, _, end, err := jalapeno.ExtractFrontMatter(source)
+	if err != nil || len(frontMatter) == 0 {
+		return jalapeno.FrontMatter{}, nil
+	}
+
+	return frontMatter, append(bytes.Clone(source[:end]), "\n\n"...)
+}
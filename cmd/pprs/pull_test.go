@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExistingFrontMatter(t *testing.T) {
+	frontMatter, block := existingFrontMatter([]byte("---\ntitle: Readme\ntags: [go]\n---\n\n# Old content\n"))
+	require.NotNil(t, frontMatter.Title())
+	assert.Equal(t, "---\ntitle: Readme\ntags: [go]\n---\n\n", string(block))
+
+	// Trailing spaces of the closing delimiter are kept
+	_, block = existingFrontMatter([]byte("+++\ntitle = \"Readme\"\n+++  \n  Indented"))
+	assert.Equal(t, "+++\ntitle = \"Readme\"\n+++  \n\n", string(block))
+
+	frontMatter, block = existingFrontMatter([]byte("# No front matter\n"))
+	assert.Nil(t, frontMatter.Title())
+	assert.Nil(t, block)
+
+	_, block = existingFrontMatter(nil)
+	assert.Nil(t, block)
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/amberpixels/peppers/internal/habanero"
+	"github.com/amberpixels/peppers/pkg/jalapeno"
+	"github.com/jomei/notionapi"
+)
+
+// docTree is a folder or a Markdown file that becomes a Notion page
+type docTree struct {
+	// Name is the name of the folder or the file (without extension)
+	// It's the title of the page unless the document has its own one
+	Name string
+
+	// Path is a slash-separated path of the Markdown file relative to the root
+	// It's empty for folders without README.md or index.md
+	Path string
+
+	Children []*docTree
+
+	folder bool
+}
+
+// isGlob returns true if the given path is a glob pattern (rather than a file or directory)
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// isMarkdown returns true if the given file name has a Markdown extension
+func isMarkdown(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// isFolderIndex returns true if the given Markdown file holds the content of its folder
+func isFolderIndex(name string) bool {
+	switch strings.ToLower(name) {
+	case "readme.md", "readme.markdown", "index.md", "index.markdown":
+		return true
+	}
+	return false
+}
+
+// collectDocs finds Markdown files in the given directory (recursively) or matching the given glob pattern
+// It returns the root directory (the directory itself or the static part of the pattern)
+// and sorted slash-separated paths of found files relative to it. Hidden directories (e.g. .git) are skipped
+func collectDocs(pattern string) (string, []string, error) {
+	files := make([]string, 0)
+
+	if !isGlob(pattern) {
+		err := filepath.WalkDir(pattern, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && p != pattern && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if !d.IsDir() && isMarkdown(d.Name()) {
+				rel, err := filepath.Rel(pattern, p)
+				if err != nil {
+					return err
+				}
+				files = append(files, filepath.ToSlash(rel))
+			}
+			return nil
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		if len(files) == 0 {
+			return "", nil, fmt.Errorf("no Markdown files found in %s", pattern)
+		}
+
+		return pattern, files, nil
+	}
+
+	// Root is the longest directory prefix without glob characters
+	root := "."
+	if parts := strings.Split(filepath.ToSlash(pattern), "/"); len(parts) > 1 {
+		static := slices.IndexFunc(parts, isGlob)
+		root = filepath.FromSlash(dirOrDot(strings.Join(parts[:static], "/"), pattern))
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			return "", nil, err
+		}
+		if info.IsDir() || !isMarkdown(match) {
+			continue
+		}
+		rel, err := filepath.Rel(root, match)
+		if err != nil {
+			return "", nil, err
+		}
+		files = append(files, filepath.ToSlash(rel))
+	}
+	if len(files) == 0 {
+		return "", nil, errors.New("no Markdown files match " + pattern)
+	}
+	slices.Sort(files)
+
+	return root, files, nil
+}
+
+// dirOrDot returns the given directory, or "." (or "/" for absolute patterns) if it's empty
+func dirOrDot(dir, pattern string) string {
+	if dir != "" {
+		return dir
+	}
+	if strings.HasPrefix(pattern, "/") {
+		return "/"
+	}
+	return "."
+}
+
+// buildDocTree arranges the given Markdown files (slash-separated paths relative to the root) into a tree:
+// folders become pages with README.md or index.md as their content, other files become their subpages
+func buildDocTree(rootName string, files []string) *docTree {
+	root := &docTree{Name: rootName, folder: true}
+
+	for _, file := range files {
+		node := root
+		if dir := path.Dir(file); dir != "." {
+			for _, name := range strings.Split(dir, "/") {
+				node = node.folderChild(name)
+			}
+		}
+
+		base := path.Base(file)
+		if isFolderIndex(base) && node.Path == "" {
+			node.Path = file
+			continue
+		}
+		node.Children = append(node.Children, &docTree{
+			Name: strings.TrimSuffix(base, path.Ext(base)),
+			Path: file,
+		})
+	}
+
+	return root
+}
+
+// folderChild returns the child folder with the given name (it's added if missing)
+func (t *docTree) folderChild(name string) *docTree {
+	for _, child := range t.Children {
+		if child.folder && child.Name == name {
+			return child
+		}
+	}
+
+	child := &docTree{Name: name, folder: true}
+	t.Children = append(t.Children, child)
+	return child
+}
+
+// newDocTree arranges the given Markdown files (relative to the root directory) into a tree named after the directory
+func newDocTree(root string, files []string) *docTree {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		ExitWithError("Couldn't resolve the directory", err)
+	}
+	return buildDocTree(filepath.Base(absRoot), files)
+}
+
+// publishTree publishes the given Markdown files (relative to the root directory) as a hierarchy of pages
+// under the parent page. With reuse, previously published pages are updated instead of creating new ones
+func publishTree(ctx context.Context, publisher *habanero.Publisher, root string, files []string, reuse bool) {
+	docs := newDocTree(root, files)
+
+	// Pages are created first, so links between documents can point at them
+	tree := &habanero.PageTree{}
+	if err := convertTree(ctx, root, docs, tree, nil); err != nil {
+		ExitWithError("Couldn't convert the directory", err)
+	}
+	if err := publisher.PrepareTree(ctx, notionapi.PageID(in.NotionParentID), tree, reuse); err != nil {
+		ExitWithError("failed to create the Notion pages", err)
+	}
+
+	pages := make(map[string]string)
+	collectPageURLs(docs, tree, pages)
+	if err := convertTree(ctx, root, docs, tree, pages); err != nil {
+		ExitWithError("Couldn't convert the directory", err)
+	}
+	if err := publisher.SyncTree(ctx, tree); err != nil {
+		ExitWithError("failed to publish the Notion pages", err)
+	}
+
+	fmt.Printf("Successfully published %d Markdown files into Notion page: %s\n", len(files), tree.Published.URL)
+}
+
+// convertTree converts documents of the given tree into pages of the page tree (missing page nodes are added)
+// Links between documents are resolved into the given page URLs
+func convertTree(ctx context.Context, root string, doc *docTree, node *habanero.PageTree, pages map[string]string) error {
+	node.Page = &habanero.Page{
+		Properties: titleProperties([]notionapi.RichText{*notionapi.NewTextRichText(doc.Name)}),
+	}
+
+	if doc.Path != "" {
+		source, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(doc.Path)))
+		if err != nil {
+			return err
+		}
+		// Documents without a title are named after their files
+		result, page, err := convertDocument(ctx, newParser(doc.Path, pages, jalapeno.WithDefaultTitle(doc.Name)), source)
+		if err != nil {
+			return fmt.Errorf("%s: %w", doc.Path, err)
+		}
+		// Documents are converted twice when published, diagnostics are the same
+		if pages == nil {
+			printDiagnostics(doc.Path, result.Diagnostics)
+		}
+		page.Dir = path.Dir(doc.Path)
+		node.Page = page
+		node.PageID = notionapi.PageID(result.FrontMatter.PageID())
+	}
+
+	for i, child := range doc.Children {
+		if i == len(node.Children) {
+			node.Children = append(node.Children, &habanero.PageTree{})
+		}
+		if err := convertTree(ctx, root, child, node.Children[i], pages); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// collectPageURLs gathers URLs of published pages by paths of their documents
+func collectPageURLs(doc *docTree, node *habanero.PageTree, pages map[string]string) {
+	if doc.Path != "" && node.Published != nil {
+		pages[doc.Path] = node.Published.URL
+	}
+	for i, child := range doc.Children {
+		collectPageURLs(child, node.Children[i], pages)
+	}
+}
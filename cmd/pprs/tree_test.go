@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, root string, files ...string) {
+	t.Helper()
+	for _, file := range files {
+		name := filepath.Join(root, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+		require.NoError(t, os.WriteFile(name, []byte("# "+file), 0o600))
+	}
+}
+
+func TestCollectDocs(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root,
+		"README.md",
+		"setup.md",
+		"guides/intro.markdown",
+		"guides/assets/diagram.png",
+		".github/PULL_REQUEST_TEMPLATE.md",
+	)
+
+	dir, files, err := collectDocs(root)
+	require.NoError(t, err)
+	assert.Equal(t, root, dir)
+	assert.Equal(t, []string{"README.md", "guides/intro.markdown", "setup.md"}, files)
+
+	dir, files, err = collectDocs(filepath.Join(root, "guides", "*.markdown"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "guides"), dir)
+	assert.Equal(t, []string{"intro.markdown"}, files)
+
+	_, _, err = collectDocs(filepath.Join(root, "*.txt"))
+	require.Error(t, err)
+}
+
+func TestBuildDocTree(t *testing.T) {
+	tree := buildDocTree("docs", []string{
+		"README.md",
+		"api/endpoints.md",
+		"api/index.md",
+		"guides/advanced/tuning.md",
+		"guides/intro.md",
+		"setup.md",
+	})
+
+	assert.Equal(t, &docTree{Name: "docs", Path: "README.md", folder: true, Children: []*docTree{
+		{Name: "api", Path: "api/index.md", folder: true, Children: []*docTree{
+			{Name: "endpoints", Path: "api/endpoints.md"},
+		}},
+		{Name: "guides", folder: true, Children: []*docTree{
+			{Name: "advanced", folder: true, Children: []*docTree{
+				{Name: "tuning", Path: "guides/advanced/tuning.md"},
+			}},
+			{Name: "intro", Path: "guides/intro.md"},
+		}},
+		{Name: "setup", Path: "setup.md"},
+	}}, tree)
+}
@@ -0,0 +1,257 @@
+package habanero
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	nt "github.com/jomei/notionapi"
+)
+
+// databaseDateLayouts are layouts of text values that can be put into date properties
+var databaseDateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+// DatabaseSchema returns property configurations of the given database
+func (p *Publisher) DatabaseSchema(ctx context.Context, databaseID nt.DatabaseID) (nt.PropertyConfigs, error) {
+	db, err := p.client.Database.Get(ctx, databaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database %s: %w", databaseID, err)
+	}
+
+	return db.Properties, nil
+}
+
+// FindDatabasePage looks for a page (row) of the given database with the given title
+// It returns ErrPageNotFound if there is no such page, or ErrAmbiguousPage if there are several ones
+func (p *Publisher) FindDatabasePage(ctx context.Context, databaseID nt.DatabaseID, schema nt.PropertyConfigs, title string) (nt.PageID, error) {
+	titleName, err := titlePropertyName(schema)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := p.client.Database.Query(ctx, databaseID, &nt.DatabaseQueryRequest{
+		Filter: nt.PropertyFilter{
+			Property: titleName,
+			RichText: &nt.TextFilterCondition{Equals: title},
+		},
+		PageSize: 100,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to query database %s: %w", databaseID, err)
+	}
+
+	// Archived pages are never returned by queries
+	switch len(resp.Results) {
+	case 0:
+		return "", fmt.Errorf("%w: no %q page in database %s", ErrPageNotFound, title, databaseID)
+	case 1:
+		return nt.PageID(resp.Results[0].ID), nil
+	default:
+		return "", fmt.Errorf("%w: %d %q pages in database %s", ErrAmbiguousPage, len(resp.Results), title, databaseID)
+	}
+}
+
+// MatchSchema validates the given page properties against the given database schema and adapts them to it:
+// the title goes into the title property of the database whatever it is called,
+// values are converted into the types of the database properties where possible (e.g. text into select).
+// All mismatches (unknown properties, inconvertible values) are reported together in the returned error.
+func MatchSchema(schema nt.PropertyConfigs, props nt.Properties) (nt.Properties, error) {
+	titleName, err := titlePropertyName(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make(nt.Properties, len(props))
+	errs := make([]error, 0)
+	for name, prop := range props {
+		if isTitleProperty(prop) {
+			name = titleName
+		}
+
+		config, ok := schema[name]
+		if !ok {
+			name, config = lookupProperty(schema, name)
+		}
+		if config == nil {
+			errs = append(errs, fmt.Errorf("property %q is not defined in the database", name))
+			continue
+		}
+
+		converted, err := convertProperty(prop, config.GetType())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("property %q: %w", name, err))
+			continue
+		}
+		matched[name] = converted
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("page properties don't match the database schema: %w", errors.Join(errs...))
+	}
+
+	return matched, nil
+}
+
+// titlePropertyName returns the name of the title property of the given schema (each database has exactly one)
+func titlePropertyName(schema nt.PropertyConfigs) (string, error) {
+	for name, config := range schema {
+		if config.GetType() == nt.PropertyConfigTypeTitle {
+			return name, nil
+		}
+	}
+
+	return "", errors.New("database has no title property")
+}
+
+// lookupProperty finds a property in the schema by its name ignoring the letter case
+func lookupProperty(schema nt.PropertyConfigs, name string) (string, nt.PropertyConfig) {
+	for schemaName, config := range schema {
+		if strings.EqualFold(schemaName, name) {
+			return schemaName, config
+		}
+	}
+
+	return name, nil
+}
+
+func isTitleProperty(prop nt.Property) bool {
+	switch prop.(type) {
+	case nt.TitleProperty, *nt.TitleProperty:
+		return true
+	default:
+		return false
+	}
+}
+
+// convertProperty converts the given property value into a property of the given type
+func convertProperty(prop nt.Property, target nt.PropertyConfigType) (nt.Property, error) {
+	text, hasText := propertyText(prop)
+
+	switch target {
+	case nt.PropertyConfigTypeTitle:
+		if richTexts := propertyRichTexts(prop); richTexts != nil {
+			return nt.TitleProperty{Title: richTexts}, nil
+		}
+		if hasText {
+			return nt.TitleProperty{Title: []nt.RichText{*nt.NewTextRichText(text)}}, nil
+		}
+	case nt.PropertyConfigTypeRichText:
+		if richTexts := propertyRichTexts(prop); richTexts != nil {
+			return nt.RichTextProperty{RichText: richTexts}, nil
+		}
+		if hasText {
+			return nt.RichTextProperty{RichText: []nt.RichText{*nt.NewTextRichText(text)}}, nil
+		}
+	case nt.PropertyConfigTypeNumber:
+		if v, ok := prop.(nt.NumberProperty); ok {
+			return v, nil
+		}
+		if number, err := strconv.ParseFloat(text, 64); hasText && err == nil {
+			return nt.NumberProperty{Number: number}, nil
+		}
+	case nt.PropertyConfigTypeCheckbox:
+		if v, ok := prop.(nt.CheckboxProperty); ok {
+			return v, nil
+		}
+		if checked, err := strconv.ParseBool(text); hasText && err == nil {
+			return nt.CheckboxProperty{Checkbox: checked}, nil
+		}
+	case nt.PropertyConfigTypeDate:
+		if v, ok := prop.(nt.DateProperty); ok {
+			return v, nil
+		}
+		for _, layout := range databaseDateLayouts {
+			if t, err := time.Parse(layout, text); hasText && err == nil {
+				date := nt.Date(t)
+				return nt.DateProperty{Date: &nt.DateObject{Start: &date}}, nil
+			}
+		}
+	case nt.PropertyConfigTypeSelect:
+		if hasText {
+			return nt.SelectProperty{Select: nt.Option{Name: text}}, nil
+		}
+	case nt.PropertyConfigStatus:
+		if hasText {
+			return nt.StatusProperty{Status: nt.Status{Name: text}}, nil
+		}
+	case nt.PropertyConfigTypeMultiSelect:
+		if v, ok := prop.(nt.MultiSelectProperty); ok {
+			return v, nil
+		}
+		if hasText {
+			options := make([]nt.Option, 0)
+			for _, name := range strings.Split(text, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					options = append(options, nt.Option{Name: name})
+				}
+			}
+			return nt.MultiSelectProperty{MultiSelect: options}, nil
+		}
+	case nt.PropertyConfigTypeURL:
+		if hasText {
+			return nt.URLProperty{URL: text}, nil
+		}
+	case nt.PropertyConfigTypeEmail:
+		if hasText {
+			return nt.EmailProperty{Email: text}, nil
+		}
+	case nt.PropertyConfigTypePhoneNumber:
+		if hasText {
+			return nt.PhoneNumberProperty{PhoneNumber: text}, nil
+		}
+	default:
+		return nil, fmt.Errorf("%s properties can't be set", target)
+	}
+
+	return nil, fmt.Errorf("%T value can't be put into %s property", prop, target)
+}
+
+// propertyRichTexts returns rich texts of text-like properties (nil for others)
+func propertyRichTexts(prop nt.Property) []nt.RichText {
+	switch v := prop.(type) {
+	case nt.TitleProperty:
+		return v.Title
+	case *nt.TitleProperty:
+		return v.Title
+	case nt.RichTextProperty:
+		return v.RichText
+	default:
+		return nil
+	}
+}
+
+// propertyText returns a plain text representation of the given scalar property
+func propertyText(prop nt.Property) (string, bool) {
+	if richTexts := propertyRichTexts(prop); richTexts != nil {
+		var text string
+		for _, rt := range richTexts {
+			text += rt.PlainText
+		}
+		return text, true
+	}
+
+	switch v := prop.(type) {
+	case nt.URLProperty:
+		return v.URL, true
+	case nt.EmailProperty:
+		return v.Email, true
+	case nt.PhoneNumberProperty:
+		return v.PhoneNumber, true
+	case nt.SelectProperty:
+		return v.Select.Name, true
+	case nt.NumberProperty:
+		return strconv.FormatFloat(v.Number, 'f', -1, 64), true
+	case nt.CheckboxProperty:
+		return strconv.FormatBool(v.Checkbox), true
+	case nt.DateProperty:
+		if v.Date == nil || v.Date.Start == nil {
+			return "", false
+		}
+		return v.Date.Start.String(), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,130 @@
+package habanero_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/amberpixels/peppers/internal/habanero"
+	nt "github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *fakeDatabases) Create(_ context.Context, _ *nt.DatabaseCreateRequest) (*nt.Database, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeDatabases) Get(_ context.Context, id nt.DatabaseID) (*nt.Database, error) {
+	db, ok := f.databases[id]
+	if !ok {
+		return nil, fmt.Errorf("database %s not found", id)
+	}
+	return db, nil
+}
+
+func (f *fakeDatabases) Update(_ context.Context, id nt.DatabaseID, _ *nt.DatabaseUpdateRequest) (*nt.Database, error) {
+	return nil, fmt.Errorf("database %s not found", id)
+}
+
+// Query supports only filtering by the title (rich text equality)
+func (f *fakeDatabases) Query(_ context.Context, id nt.DatabaseID, req *nt.DatabaseQueryRequest) (*nt.DatabaseQueryResponse, error) {
+	filter, ok := req.Filter.(nt.PropertyFilter)
+	if !ok || filter.RichText == nil {
+		return nil, fmt.Errorf("unsupported filter: %#v", req.Filter)
+	}
+
+	resp := &nt.DatabaseQueryResponse{Results: make([]nt.Page, 0)}
+	for _, page := range f.pages {
+		if page.Parent.DatabaseID != id {
+			continue
+		}
+		if habanero.PageTitle(nt.Properties{filter.Property: page.Properties[filter.Property]}) == filter.RichText.Equals {
+			resp.Results = append(resp.Results, *page)
+		}
+	}
+	return resp, nil
+}
+
+// docsSchema is a schema of a database where the title property is not called "title"
+var docsSchema = nt.PropertyConfigs{
+	"Name":        nt.TitlePropertyConfig{Type: nt.PropertyConfigTypeTitle},
+	"Repo":        nt.SelectPropertyConfig{Type: nt.PropertyConfigTypeSelect},
+	"Tags":        nt.MultiSelectPropertyConfig{Type: nt.PropertyConfigTypeMultiSelect},
+	"Last Synced": nt.DatePropertyConfig{Type: nt.PropertyConfigTypeDate},
+	"Stars":       nt.NumberPropertyConfig{Type: nt.PropertyConfigTypeNumber},
+	"Words":       nt.FormulaPropertyConfig{Type: nt.PropertyConfigTypeFormula},
+}
+
+func textProperty(text string) nt.RichTextProperty {
+	return nt.RichTextProperty{RichText: []nt.RichText{*nt.NewTextRichText(text)}}
+}
+
+func TestMatchSchema(t *testing.T) {
+	synced := nt.Date(time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC))
+
+	props, err := habanero.MatchSchema(docsSchema, nt.Properties{
+		"title":       titleProps("Readme")["title"],
+		"Repo":        textProperty("peppers"),
+		"tags":        nt.MultiSelectProperty{MultiSelect: []nt.Option{{Name: "go"}}},
+		"Last Synced": textProperty("2024-12-21"),
+		"Stars":       textProperty("42"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, nt.Properties{
+		"Name":        nt.TitleProperty{Title: []nt.RichText{*nt.NewTextRichText("Readme")}},
+		"Repo":        nt.SelectProperty{Select: nt.Option{Name: "peppers"}},
+		"Tags":        nt.MultiSelectProperty{MultiSelect: []nt.Option{{Name: "go"}}},
+		"Last Synced": nt.DateProperty{Date: &nt.DateObject{Start: &synced}},
+		"Stars":       nt.NumberProperty{Number: 42},
+	}, props)
+
+	_, err = habanero.MatchSchema(docsSchema, nt.Properties{
+		"Path":  textProperty("docs/readme.md"),
+		"Stars": nt.CheckboxProperty{Checkbox: true},
+		"Words": textProperty("100"),
+	})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `property "Path" is not defined in the database`)
+	assert.ErrorContains(t, err, `property "Stars": notionapi.CheckboxProperty value can't be put into number property`)
+	assert.ErrorContains(t, err, `property "Words": formula properties can't be set`)
+
+	_, err = habanero.MatchSchema(nt.PropertyConfigs{}, titleProps("Readme"))
+	require.Error(t, err)
+}
+
+func TestPublisher_DatabasePages(t *testing.T) {
+	notion := newFakeNotion()
+	databaseID := nt.DatabaseID("docs")
+	notion.databases[databaseID] = &nt.Database{ID: nt.ObjectID(databaseID), Properties: docsSchema}
+	publisher := habanero.NewPublisher(notion.Client())
+
+	schema, err := publisher.DatabaseSchema(context.Background(), databaseID)
+	require.NoError(t, err)
+
+	props, err := habanero.MatchSchema(schema, titleProps("Readme"))
+	require.NoError(t, err)
+
+	created, err := publisher.Create(context.Background(), nt.Parent{
+		Type:       nt.ParentTypeDatabaseID,
+		DatabaseID: databaseID,
+	}, &habanero.Page{Properties: props})
+	require.NoError(t, err)
+
+	pageID, err := publisher.FindDatabasePage(context.Background(), databaseID, schema, "Readme")
+	require.NoError(t, err)
+	assert.Equal(t, nt.PageID(created.ID), pageID)
+
+	_, err = publisher.FindDatabasePage(context.Background(), databaseID, schema, "Missing")
+	require.ErrorIs(t, err, habanero.ErrPageNotFound)
+
+	_, err = publisher.Create(context.Background(), nt.Parent{
+		Type:       nt.ParentTypeDatabaseID,
+		DatabaseID: databaseID,
+	}, &habanero.Page{Properties: props})
+	require.NoError(t, err)
+
+	_, err = publisher.FindDatabasePage(context.Background(), databaseID, schema, "Readme")
+	require.ErrorIs(t, err, habanero.ErrAmbiguousPage)
+}
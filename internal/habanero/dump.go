@@ -0,0 +1,89 @@
+package habanero
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	nt "github.com/jomei/notionapi"
+)
+
+// PageRequest returns the request that creates the given page with all its content at once
+// (as if Notion API had no limits: Create splits it into several requests)
+// Local images are resolved the same way as on publishing
+func (p *Publisher) PageRequest(ctx context.Context, parent nt.Parent, page *Page) (*nt.PageCreateRequest, error) {
+	blocks, err := p.resolveImages(ctx, page.Dir, page.Blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nt.PageCreateRequest{
+		Parent:     parent,
+		Properties: page.Properties,
+		Icon:       page.Icon,
+		Cover:      page.Cover,
+		Children:   blocks,
+	}, nil
+}
+
+// DumpPage writes a human-readable outline of the given page: its title and the tree of its blocks,
+// one block per line in a form of "<indent><type>: <text>"
+// Depth is the indentation level of the page itself (e.g. for subpages of a tree)
+func DumpPage(w io.Writer, page *Page, depth int) error {
+	if _, err := fmt.Fprintf(w, "%spage: %s\n", strings.Repeat("  ", depth), PageTitle(page.Properties)); err != nil {
+		return err
+	}
+	return dumpBlocks(w, page.Blocks, depth+1)
+}
+
+func dumpBlocks(w io.Writer, blocks nt.Blocks, depth int) error {
+	for _, block := range blocks {
+		line := strings.TrimRight(fmt.Sprintf("%s%s: %s", strings.Repeat("  ", depth), block.GetType(), blockText(block)), " ")
+		if _, err := fmt.Fprintln(w, strings.ReplaceAll(line, "\n", `\n`)); err != nil {
+			return err
+		}
+		if err := dumpBlocks(w, blockChildren(block), depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blockText returns a short plain text summary of the block content
+func blockText(block nt.Block) string {
+	switch v := block.(type) {
+	case *nt.CodeBlock:
+		return fmt.Sprintf("[%s] %s", v.Code.Language, richTextsPlain(v.Code.RichText))
+	case *nt.TableRowBlock:
+		cells := make([]string, len(v.TableRow.Cells))
+		for i, cell := range v.TableRow.Cells {
+			cells[i] = richTextsPlain(cell)
+		}
+		return strings.Join(cells, " | ")
+	case *nt.ImageBlock:
+		text := richTextsPlain(v.Image.Caption)
+		if v.Image.External != nil {
+			text = strings.TrimSpace(v.Image.External.URL + " " + text)
+		}
+		return text
+	case *uploadedImageBlock:
+		return strings.TrimSpace("file_upload:" + v.Image.FileUpload.ID + " " + richTextsPlain(v.Image.Caption))
+	case *nt.ChildPageBlock:
+		return v.ChildPage.Title
+	case *nt.ParagraphBlock, *nt.Heading1Block, *nt.Heading2Block, *nt.Heading3Block,
+		*nt.CalloutBlock, *nt.QuoteBlock, *nt.BulletedListItemBlock, *nt.NumberedListItemBlock,
+		*nt.ToDoBlock, *nt.ToggleBlock, *nt.EquationBlock:
+		return block.GetRichTextString()
+	default:
+		return ""
+	}
+}
+
+func richTextsPlain(richTexts []nt.RichText) string {
+	var sb strings.Builder
+	for _, rt := range richTexts {
+		sb.WriteString(rt.PlainText)
+	}
+	return sb.String()
+}
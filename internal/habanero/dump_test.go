@@ -0,0 +1,65 @@
+package habanero_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/amberpixels/peppers/internal/habanero"
+	nt "github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublisher_PageRequest(t *testing.T) {
+	baseURL, err := url.Parse("https://raw.githubusercontent.com/amberpixels/peppers/main/")
+	require.NoError(t, err)
+
+	// No client is needed to prepare the request
+	publisher := habanero.NewPublisher(nil, habanero.WithImageBaseURL(baseURL))
+
+	blocks := nt.Blocks{paragraph("Intro")}
+	blocks = append(blocks, imageBlock("arch.png")...)
+
+	req, err := publisher.PageRequest(context.Background(), nt.Parent{
+		Type:   nt.ParentTypePageID,
+		PageID: "parent",
+	}, &habanero.Page{Properties: titleProps("Guide"), Blocks: blocks, Dir: "docs"})
+	require.NoError(t, err)
+
+	assert.Equal(t, nt.PageID("parent"), req.Parent.PageID)
+	assert.Equal(t, "Guide", habanero.PageTitle(req.Properties))
+	require.Len(t, req.Children, 2)
+	assert.Equal(t, "https://raw.githubusercontent.com/amberpixels/peppers/main/docs/arch.png",
+		req.Children[1].(*nt.ImageBlock).Image.External.URL)
+}
+
+func TestDumpPage(t *testing.T) {
+	var out strings.Builder
+	err := habanero.DumpPage(&out, &habanero.Page{
+		Properties: titleProps("Guide"),
+		Blocks: nt.Blocks{
+			paragraph("Multi\nline"),
+			bulletedItem("Level 1", bulletedItem("Level 2")),
+			nt.NewCodeBlock(nt.Code{Language: "go", RichText: []nt.RichText{*nt.NewTextRichText("x := 1")}}),
+			nt.NewDividerBlock(),
+			nt.NewTableBlock(nt.Table{TableWidth: 2, Children: nt.Blocks{
+				nt.NewTableRowBlock(nt.TableRow{Cells: [][]nt.RichText{
+					{*nt.NewTextRichText("a")}, {*nt.NewTextRichText("b")},
+				}}),
+			}}),
+		},
+	}, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, `  page: Guide
+    paragraph: Multi\nline
+    bulleted_list_item: Level 1
+      bulleted_list_item: Level 2
+    code: [go] x := 1
+    divider:
+    table:
+      table_row: a | b
+`, out.String())
+}
@@ -0,0 +1,287 @@
+// Package habanero is a library that publishes converted Notion blocks into Notion
+package habanero
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	nt "github.com/jomei/notionapi"
+)
+
+// ErrPageNotFound is returned when no previously published page can be found
+var ErrPageNotFound = errors.New("notion page not found")
+
+// ErrAmbiguousPage is returned when several previously published pages have the same title
+var ErrAmbiguousPage = errors.New("several notion pages found")
+
+// Publisher stands for an instance that pushes pages into Notion via the given client
+type Publisher struct {
+	client *nt.Client
+
+	// baseDir is a directory relative paths of local images are resolved against
+	baseDir string
+	// imageBaseURL (if set) is an URL local images are rewritten to (instead of being uploaded)
+	imageBaseURL *url.URL
+	// uploader (if set) uploads local images into Notion
+	uploader FileUploader
+	// uploads caches IDs of already uploaded files (by their paths)
+	uploads map[string]string
+}
+
+// PublisherOption configures the Publisher
+type PublisherOption func(*Publisher)
+
+// WithBaseDir sets the directory relative paths of local images are resolved against
+// (normally it's the directory of the Markdown file or the root of the converted directory tree, see Page.Dir)
+func WithBaseDir(dir string) PublisherOption {
+	return func(p *Publisher) { p.baseDir = dir }
+}
+
+// WithImageBaseURL makes local images be rewritten into URLs relative to the given one standing for the base directory
+// (e.g. a raw GitHub URL of the Markdown file's directory) instead of being uploaded
+func WithImageBaseURL(baseURL *url.URL) PublisherOption {
+	return func(p *Publisher) {
+		rebased := *baseURL
+		if !strings.HasSuffix(rebased.Path, "/") {
+			rebased.Path += "/"
+		}
+		p.imageBaseURL = &rebased
+	}
+}
+
+// WithFileUploader makes local images be uploaded into Notion via the given uploader
+func WithFileUploader(uploader FileUploader) PublisherOption {
+	return func(p *Publisher) { p.uploader = uploader }
+}
+
+func NewPublisher(client *nt.Client, opts ...PublisherOption) *Publisher {
+	p := &Publisher{client: client, uploads: make(map[string]string)}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Page is a converted document ready to be published into Notion
+type Page struct {
+	Properties nt.Properties
+	Icon       *nt.Icon
+	Cover      *nt.Image
+	Blocks     nt.Blocks
+
+	// Dir is a slash-separated directory of the page source relative to the base directory (see WithBaseDir)
+	// Relative paths of local images are resolved against it
+	Dir string
+}
+
+// Create creates a new Notion page under the given parent
+// The page is created with the first batch of blocks, the rest is appended in follow-up requests
+func (p *Publisher) Create(ctx context.Context, parent nt.Parent, page *Page) (*nt.Page, error) {
+	blocks, err := p.resolveImages(ctx, page.Dir, page.Blocks)
+	if err != nil {
+		return nil, err
+	}
+	firstBatch, rest := splitFirstBatch(prepareBlocks(blocks))
+
+	created, err := p.client.Page.Create(ctx, &nt.PageCreateRequest{
+		Parent:     parent,
+		Properties: page.Properties,
+		Icon:       page.Icon,
+		Cover:      page.Cover,
+		Children:   firstBatch,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create page: %w", err)
+	}
+
+	if err := p.appendPending(ctx, nt.BlockID(created.ID), "", rest); err != nil {
+		return nil, fmt.Errorf("failed to append page content: %w", err)
+	}
+
+	return created, nil
+}
+
+// Sync updates an existing Notion page in place:
+// its properties (icon and cover) are replaced by the given ones and all its content blocks are replaced by the given blocks
+// Nested pages and databases living inside the page are kept untouched
+// New content is appended before the previous one is deleted, so a failed sync doesn't leave the page empty
+func (p *Publisher) Sync(ctx context.Context, pageID nt.PageID, page *Page) (*nt.Page, error) {
+	blocks, err := p.resolveImages(ctx, page.Dir, page.Blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := p.client.Page.Update(ctx, pageID, &nt.PageUpdateRequest{
+		Properties: page.Properties,
+		Icon:       page.Icon,
+		Cover:      page.Cover,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update page properties: %w", err)
+	}
+
+	previous, err := p.listChildren(ctx, nt.BlockID(pageID))
+	if err != nil {
+		return nil, err
+	}
+
+	// New content goes first, so the page is never left empty if publishing fails halfway
+	// It's placed ahead of subpages if the previous content is there (see PrepareTree)
+	if err := p.appendPending(ctx, nt.BlockID(pageID), contentEnd(previous), prepareBlocks(blocks)); err != nil {
+		return nil, fmt.Errorf("failed to append page content (previous content is kept, the new one may be partially appended): %w", err)
+	}
+
+	if err := p.deleteContent(ctx, previous); err != nil {
+		return nil, fmt.Errorf("failed to delete previous page content (it's kept above the new one): %w", err)
+	}
+
+	return updated, nil
+}
+
+// FindChildPage looks for a page (placed directly under the given parent page) with the given title
+// It returns ErrPageNotFound if there is no such page
+func (p *Publisher) FindChildPage(ctx context.Context, parentID nt.PageID, title string) (nt.PageID, error) {
+	children, err := p.listChildren(ctx, nt.BlockID(parentID))
+	if err != nil {
+		return "", err
+	}
+
+	for _, child := range children {
+		childPage, ok := child.(*nt.ChildPageBlock)
+		if !ok || childPage.Archived {
+			continue
+		}
+		if childPage.ChildPage.Title == title {
+			return nt.PageID(childPage.GetID()), nil
+		}
+	}
+
+	return "", ErrPageNotFound
+}
+
+// Fetch returns the given Notion page with all its content blocks (nested ones included)
+func (p *Publisher) Fetch(ctx context.Context, pageID nt.PageID) (*Page, error) {
+	page, err := p.client.Page.Get(ctx, pageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get page: %w", err)
+	}
+
+	blocks, err := p.fetchBlocks(ctx, nt.BlockID(pageID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Page{
+		Properties: page.Properties,
+		Icon:       page.Icon,
+		Cover:      page.Cover,
+		Blocks:     blocks,
+	}, nil
+}
+
+// fetchBlocks returns all children of the given block with their nested children
+// Nested pages and databases are separate documents, so their content is not fetched
+func (p *Publisher) fetchBlocks(ctx context.Context, blockID nt.BlockID) (nt.Blocks, error) {
+	children, err := p.listChildren(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, child := range children {
+		if !child.GetHasChildren() {
+			continue
+		}
+		switch child.GetType() {
+		case nt.BlockTypeChildPage, nt.BlockTypeChildDatabase:
+			continue
+		}
+
+		nested, err := p.fetchBlocks(ctx, child.GetID())
+		if err != nil {
+			return nil, err
+		}
+		children[i] = withBlockChildren(child, nested)
+	}
+
+	return children, nil
+}
+
+// contentEnd returns the last content block followed by nested pages or databases among the given children of a page,
+// new content is inserted after it to keep nested pages below the content. It's empty if there is no such block
+func contentEnd(children nt.Blocks) nt.BlockID {
+	for i, child := range children {
+		switch child.GetType() {
+		case nt.BlockTypeChildPage, nt.BlockTypeChildDatabase:
+			if i == 0 {
+				return ""
+			}
+			return children[i-1].GetID()
+		}
+	}
+	return ""
+}
+
+// deleteContent deletes the given blocks that are content (of a page)
+// Child pages and child databases are not content, so they are kept
+func (p *Publisher) deleteContent(ctx context.Context, children nt.Blocks) error {
+	for _, child := range children {
+		switch child.GetType() {
+		case nt.BlockTypeChildPage, nt.BlockTypeChildDatabase:
+			continue
+		}
+
+		if _, err := p.client.Block.Delete(ctx, child.GetID()); err != nil {
+			return fmt.Errorf("failed to delete block %s: %w", child.GetID(), err)
+		}
+	}
+
+	return nil
+}
+
+// listChildren returns all (first-level) children of the given block walking through all result pages
+func (p *Publisher) listChildren(ctx context.Context, blockID nt.BlockID) (nt.Blocks, error) {
+	result := make(nt.Blocks, 0)
+
+	var cursor nt.Cursor
+	for {
+		resp, err := p.client.Block.GetChildren(ctx, blockID, &nt.Pagination{
+			StartCursor: cursor,
+			PageSize:    100,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list children of %s: %w", blockID, err)
+		}
+
+		result = append(result, resp.Results...)
+		if !resp.HasMore || resp.NextCursor == "" {
+			return result, nil
+		}
+		cursor = nt.Cursor(resp.NextCursor)
+	}
+}
+
+// PageTitle returns the plain text of the title property of the given page properties
+func PageTitle(props nt.Properties) string {
+	for _, prop := range props {
+		var richTexts []nt.RichText
+		switch v := prop.(type) {
+		case nt.TitleProperty:
+			richTexts = v.Title
+		case *nt.TitleProperty:
+			richTexts = v.Title
+		default:
+			continue
+		}
+
+		var title string
+		for _, rt := range richTexts {
+			title += rt.PlainText
+		}
+		return title
+	}
+
+	return ""
+}
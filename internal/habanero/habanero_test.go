@@ -0,0 +1,441 @@
+package habanero_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/amberpixels/peppers/internal/habanero"
+	nt "github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNotion is an in-memory implementation of the Notion Page, Block and Database services
+type fakeNotion struct {
+	pages     map[nt.PageID]*nt.Page
+	databases map[nt.DatabaseID]*nt.Database
+	children  map[nt.BlockID]nt.Blocks
+	deleted   []nt.BlockID
+	requests  []nt.Blocks
+	pageSize  int
+	lastID    int
+
+	failAppends bool // makes appending children fail
+}
+
+func newFakeNotion() *fakeNotion {
+	return &fakeNotion{
+		pages:     make(map[nt.PageID]*nt.Page),
+		databases: make(map[nt.DatabaseID]*nt.Database),
+		children:  make(map[nt.BlockID]nt.Blocks),
+		pageSize:  2,
+	}
+}
+
+func (f *fakeNotion) Client() *nt.Client {
+	return &nt.Client{Page: (*fakePages)(f), Block: (*fakeBlocks)(f), Database: (*fakeDatabases)(f)}
+}
+
+// fakePages implements nt.PageService over fakeNotion
+type fakePages fakeNotion
+
+// fakeBlocks implements nt.BlockService over fakeNotion
+type fakeBlocks fakeNotion
+
+// fakeDatabases implements nt.DatabaseService over fakeNotion
+type fakeDatabases fakeNotion
+
+func (f *fakeNotion) nextID() string {
+	f.lastID++
+	return fmt.Sprintf("id-%d", f.lastID)
+}
+
+// store saves given blocks as children of the given parent (assigning them new IDs)
+// right after the given child block (or at the bottom if it's empty)
+// It returns stored blocks as Notion API would do
+func (f *fakeNotion) store(parentID, after nt.BlockID, blocks nt.Blocks) (nt.Blocks, error) {
+	f.requests = append(f.requests, blocks)
+
+	// JSON round trip is the easiest way to set ID on any kind of block
+	raw, err := json.Marshal(blocks)
+	if err != nil {
+		return nil, err
+	}
+	generic := make([]map[string]any, 0)
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, err
+	}
+	for _, b := range generic {
+		b["id"] = f.nextID()
+	}
+	if raw, err = json.Marshal(generic); err != nil {
+		return nil, err
+	}
+	var stored nt.Blocks
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		return nil, err
+	}
+
+	existing := f.children[parentID]
+	at := len(existing)
+	if after != "" {
+		at = slices.IndexFunc(existing, func(b nt.Block) bool { return b.GetID() == after })
+		if at < 0 {
+			return nil, fmt.Errorf("block %s not found in %s", after, parentID)
+		}
+		at++
+	}
+	f.children[parentID] = slices.Concat(existing[:at], stored, existing[at:])
+	return stored, nil
+}
+
+func (f *fakePages) Create(_ context.Context, req *nt.PageCreateRequest) (*nt.Page, error) {
+	page := &nt.Page{
+		ID:         nt.ObjectID((*fakeNotion)(f).nextID()),
+		Properties: req.Properties,
+		Parent:     req.Parent,
+		Icon:       req.Icon,
+		Cover:      req.Cover,
+	}
+	page.URL = "https://notion.so/" + string(page.ID)
+	f.pages[nt.PageID(page.ID)] = page
+	if req.Parent.Type == nt.ParentTypePageID {
+		// as Notion does, the page is shown as a block of its parent page
+		childPage := nt.NewChildPageBlock(habanero.PageTitle(req.Properties))
+		childPage.ID = nt.BlockID(page.ID)
+		f.children[nt.BlockID(req.Parent.PageID)] = append(f.children[nt.BlockID(req.Parent.PageID)], childPage)
+	}
+	if _, err := (*fakeNotion)(f).store(nt.BlockID(page.ID), "", req.Children); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (f *fakePages) Get(_ context.Context, id nt.PageID) (*nt.Page, error) {
+	page, ok := f.pages[id]
+	if !ok {
+		return nil, fmt.Errorf("page %s not found", id)
+	}
+	return page, nil
+}
+
+func (f *fakePages) Update(_ context.Context, id nt.PageID, req *nt.PageUpdateRequest) (*nt.Page, error) {
+	page, ok := f.pages[id]
+	if !ok {
+		return nil, fmt.Errorf("page %s not found", id)
+	}
+	page.Properties = req.Properties
+	if req.Icon != nil {
+		page.Icon = req.Icon
+	}
+	if req.Cover != nil {
+		page.Cover = req.Cover
+	}
+	return page, nil
+}
+
+func (f *fakeBlocks) AppendChildren(_ context.Context, id nt.BlockID, req *nt.AppendBlockChildrenRequest) (*nt.AppendBlockChildrenResponse, error) {
+	if len(req.Children) > habanero.MaxChildrenPerRequest {
+		return nil, fmt.Errorf("too many children: %d", len(req.Children))
+	}
+	if f.failAppends {
+		return nil, fmt.Errorf("failed to append children to %s", id)
+	}
+	stored, err := (*fakeNotion)(f).store(id, req.After, req.Children)
+	if err != nil {
+		return nil, err
+	}
+	return &nt.AppendBlockChildrenResponse{Results: stored}, nil
+}
+
+func (f *fakeBlocks) GetChildren(_ context.Context, id nt.BlockID, pagination *nt.Pagination) (*nt.GetChildrenResponse, error) {
+	all := f.children[id]
+
+	var offset int
+	if pagination != nil && pagination.StartCursor != "" {
+		_, _ = fmt.Sscanf(string(pagination.StartCursor), "%d", &offset)
+	}
+	end := min(offset+f.pageSize, len(all))
+
+	resp := &nt.GetChildrenResponse{Results: all[offset:end]}
+	if end < len(all) {
+		resp.HasMore = true
+		resp.NextCursor = fmt.Sprintf("%d", end)
+	}
+	return resp, nil
+}
+
+func (f *fakeBlocks) Delete(_ context.Context, id nt.BlockID) (nt.Block, error) {
+	f.deleted = append(f.deleted, id)
+	for parentID, blocks := range f.children {
+		for i, b := range blocks {
+			if b.GetID() == id {
+				f.children[parentID] = append(blocks[:i:i], blocks[i+1:]...)
+				return b, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("block %s not found", id)
+}
+
+func (f *fakeBlocks) Get(_ context.Context, id nt.BlockID) (nt.Block, error) {
+	return nil, fmt.Errorf("block %s not found", id)
+}
+
+func (f *fakeBlocks) Update(_ context.Context, id nt.BlockID, _ *nt.BlockUpdateRequest) (nt.Block, error) {
+	return nil, fmt.Errorf("block %s not found", id)
+}
+
+func titleProps(title string) nt.Properties {
+	return nt.Properties{
+		string(nt.PropertyConfigTypeTitle): nt.TitleProperty{
+			Title: []nt.RichText{*nt.NewTextRichText(title)},
+		},
+	}
+}
+
+func TestPublisher_FindChildPage(t *testing.T) {
+	notion := newFakeNotion()
+	parentID := nt.BlockID("parent")
+
+	readme := nt.NewChildPageBlock("Readme")
+	readme.ID = "readme-page"
+	archived := nt.NewChildPageBlock("Archived")
+	archived.ID = "archived-page"
+	archived.Archived = true
+	paragraph := nt.NewParagraphBlock(nt.Paragraph{})
+	paragraph.ID = "paragraph"
+	notion.children[parentID] = nt.Blocks{paragraph, archived, nt.NewDividerBlock(), readme}
+
+	publisher := habanero.NewPublisher(notion.Client())
+
+	pageID, err := publisher.FindChildPage(context.Background(), nt.PageID(parentID), "Readme")
+	require.NoError(t, err)
+	assert.Equal(t, nt.PageID("readme-page"), pageID)
+
+	_, err = publisher.FindChildPage(context.Background(), nt.PageID(parentID), "Archived")
+	require.ErrorIs(t, err, habanero.ErrPageNotFound)
+
+	_, err = publisher.FindChildPage(context.Background(), nt.PageID(parentID), "Missing")
+	require.ErrorIs(t, err, habanero.ErrPageNotFound)
+}
+
+func TestPublisher_Sync(t *testing.T) {
+	notion := newFakeNotion()
+	publisher := habanero.NewPublisher(notion.Client())
+
+	page, err := publisher.Create(context.Background(), nt.Parent{
+		Type:   nt.ParentTypePageID,
+		PageID: "parent",
+	}, &habanero.Page{Properties: titleProps("Old title")})
+	require.NoError(t, err)
+	pageID := nt.PageID(page.ID)
+
+	oldParagraph := nt.NewParagraphBlock(nt.Paragraph{})
+	oldParagraph.ID = "old-paragraph"
+	oldDivider := nt.NewDividerBlock()
+	oldDivider.ID = "old-divider"
+	subPage := nt.NewChildPageBlock("Sub page")
+	subPage.ID = "sub-page"
+	oldCode := nt.NewCodeBlock(nt.Code{})
+	oldCode.ID = "old-code"
+	notion.children[nt.BlockID(pageID)] = nt.Blocks{oldParagraph, oldDivider, subPage, oldCode}
+
+	newBlocks := nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{*nt.NewTextRichText("New content")},
+		}),
+	}
+	emoji := nt.Emoji("🌶️")
+
+	synced, err := publisher.Sync(context.Background(), pageID, &habanero.Page{
+		Properties: titleProps("New title"),
+		Icon:       &nt.Icon{Type: "emoji", Emoji: &emoji},
+		Blocks:     newBlocks,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "New title", habanero.PageTitle(synced.Properties))
+	require.NotNil(t, synced.Icon)
+	assert.Equal(t, emoji, *synced.Icon.Emoji)
+	assert.Equal(t, []nt.BlockID{"old-paragraph", "old-divider", "old-code"}, notion.deleted)
+	// New content takes the place of the previous one above nested pages
+	assert.Equal(t, []string{
+		"paragraph: New content",
+		"child_page: ",
+	}, outline(t, notion.children[nt.BlockID(pageID)], notion))
+
+	// Previous content is kept if the new one can't be published
+	notion.failAppends = true
+	_, err = publisher.Sync(context.Background(), pageID, &habanero.Page{
+		Properties: titleProps("New title"),
+		Blocks:     nt.Blocks{paragraph("Newer content")},
+	})
+	require.Error(t, err)
+	assert.Equal(t, []string{
+		"paragraph: New content",
+		"child_page: ",
+	}, outline(t, notion.children[nt.BlockID(pageID)], notion))
+}
+
+// outline returns a human-readable tree of given blocks in a form of lines: "<indent><type>: <text>"
+// If fake is given, children stored in fake (appended by separate requests) are included as well
+func outline(t *testing.T, blocks nt.Blocks, fake *fakeNotion) []string {
+	t.Helper()
+
+	raw, err := json.Marshal(blocks)
+	require.NoError(t, err)
+	generic := make([]map[string]any, 0)
+	require.NoError(t, json.Unmarshal(raw, &generic))
+
+	lines := make([]string, 0)
+	var walk func(blocks []map[string]any, depth int)
+	walk = func(blocks []map[string]any, depth int) {
+		for _, b := range blocks {
+			blockType := b["type"].(string) // nolint:errcheck
+			content, _ := b[blockType].(map[string]any)
+
+			var text string
+			if rts, ok := content["rich_text"].([]any); ok {
+				for _, rt := range rts {
+					text += rt.(map[string]any)["plain_text"].(string) // nolint:errcheck
+				}
+			}
+			lines = append(lines, fmt.Sprintf("%s%s: %s", strings.Repeat("  ", depth), blockType, text))
+
+			if children, ok := content["children"].([]any); ok {
+				nested := make([]map[string]any, len(children))
+				for i, child := range children {
+					nested[i] = child.(map[string]any) // nolint:errcheck
+				}
+				walk(nested, depth+1)
+			}
+			if id, ok := b["id"].(string); ok && fake != nil {
+				lines = append(lines, indent(outline(t, fake.children[nt.BlockID(id)], fake), depth+1)...)
+			}
+		}
+	}
+	walk(generic, 0)
+
+	return lines
+}
+
+func indent(lines []string, depth int) []string {
+	for i := range lines {
+		lines[i] = strings.Repeat("  ", depth) + lines[i]
+	}
+	return lines
+}
+
+// nestingDepth returns the maximum level of nesting of given blocks
+func nestingDepth(t *testing.T, blocks nt.Blocks) int {
+	t.Helper()
+
+	maxDepth := 0
+	for _, line := range outline(t, blocks, nil) {
+		depth := (len(line)-len(strings.TrimLeft(line, " ")))/2 + 1
+		maxDepth = max(maxDepth, depth)
+	}
+	return maxDepth
+}
+
+func paragraph(text string, children ...nt.Block) nt.Block {
+	return nt.NewParagraphBlock(nt.Paragraph{
+		RichText: []nt.RichText{*nt.NewTextRichText(text)},
+		Children: children,
+	})
+}
+
+func bulletedItem(text string, children ...nt.Block) nt.Block {
+	return nt.NewBulletedListItemBlock(nt.ListItem{
+		RichText: []nt.RichText{*nt.NewTextRichText(text)},
+		Children: children,
+	})
+}
+
+func TestPublisher_Create_RespectsLimits(t *testing.T) {
+	blocks := make(nt.Blocks, 0)
+	for i := range 150 {
+		blocks = append(blocks, paragraph(fmt.Sprintf("Paragraph %d", i)))
+	}
+
+	// 4 levels deep list
+	blocks = append(blocks, bulletedItem("Level 1",
+		bulletedItem("Level 2.1",
+			bulletedItem("Level 3",
+				bulletedItem("Level 4"),
+			),
+		),
+		bulletedItem("Level 2.2"),
+	))
+
+	// Table with too many rows
+	rows := make(nt.Blocks, 0)
+	for i := range 120 {
+		rows = append(rows, nt.NewTableRowBlock(nt.TableRow{
+			Cells: [][]nt.RichText{{*nt.NewTextRichText(fmt.Sprintf("Cell %d", i))}},
+		}))
+	}
+	blocks = append(blocks, nt.NewTableBlock(nt.Table{TableWidth: 1, Children: rows}))
+
+	// Paragraph with too many children
+	manyChildren := make(nt.Blocks, 0)
+	for i := range 130 {
+		manyChildren = append(manyChildren, paragraph(fmt.Sprintf("Child %d", i)))
+	}
+	blocks = append(blocks, paragraph("Parent", manyChildren...))
+
+	blocks = append(blocks, paragraph("The end"))
+
+	expected := outline(t, blocks, nil)
+
+	notion := newFakeNotion()
+	publisher := habanero.NewPublisher(notion.Client())
+
+	page, err := publisher.Create(context.Background(), nt.Parent{
+		Type:   nt.ParentTypePageID,
+		PageID: "parent",
+	}, &habanero.Page{Properties: titleProps("Big page"), Blocks: blocks})
+	require.NoError(t, err)
+
+	require.Greater(t, len(notion.requests), 1, "content must be split into several requests")
+	for i, req := range notion.requests {
+		assert.LessOrEqual(t, len(req), habanero.MaxChildrenPerRequest, "request %d has too many children", i)
+		assert.LessOrEqual(t, nestingDepth(t, req), 2, "request %d is nested too deep", i)
+	}
+	assert.Len(t, notion.requests[0], 100, "page must be created with the first batch")
+
+	assert.Equal(t, expected, outline(t, notion.children[nt.BlockID(page.ID)], notion))
+}
+
+func TestPublisher_Fetch(t *testing.T) {
+	notion := newFakeNotion()
+	notion.pages["page"] = &nt.Page{ID: "page", Properties: titleProps("Readme")}
+
+	item := nt.NewBulletedListItemBlock(nt.ListItem{RichText: []nt.RichText{*nt.NewTextRichText("Item")}})
+	item.ID, item.HasChildren = "item", true
+	nested := nt.NewBulletedListItemBlock(nt.ListItem{RichText: []nt.RichText{*nt.NewTextRichText("Nested")}})
+	nested.ID = "nested"
+	subPage := nt.NewChildPageBlock("Sub page")
+	subPage.ID, subPage.HasChildren = "sub-page", true
+	notion.children["page"] = nt.Blocks{paragraph("Intro"), item, subPage}
+	notion.children["item"] = nt.Blocks{nested}
+	notion.children["sub-page"] = nt.Blocks{paragraph("Not fetched")}
+
+	publisher := habanero.NewPublisher(notion.Client())
+	page, err := publisher.Fetch(context.Background(), "page")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Readme", habanero.PageTitle(page.Properties))
+	assert.Equal(t, []string{
+		"paragraph: Intro",
+		"bulleted_list_item: Item",
+		"  bulleted_list_item: Nested",
+		"child_page: ",
+	}, outline(t, page.Blocks, nil))
+}
@@ -0,0 +1,268 @@
+package habanero
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	nt "github.com/jomei/notionapi"
+)
+
+// NotionAPIURL is the base URL of Notion API
+const NotionAPIURL = "https://api.notion.com"
+
+// notionVersion is the version of Notion API used for requests that notionapi doesn't support yet
+const notionVersion = "2022-06-28"
+
+// FileUploader uploads local files into Notion, so they can be referenced from blocks
+type FileUploader interface {
+	// Upload uploads the file with the given name and content, it returns the ID of the file upload
+	Upload(ctx context.Context, name string, content []byte) (string, error)
+}
+
+// NotionFileUploader is a FileUploader that works via Notion's File Upload API
+// (notionapi doesn't support it yet, so it's implemented over plain HTTP)
+type NotionFileUploader struct {
+	token   nt.Token
+	baseURL string
+	http    *http.Client
+}
+
+// NewFileUploader creates a FileUploader for the Notion API placed at the given base URL (see NotionAPIURL)
+func NewFileUploader(token nt.Token, baseURL string) *NotionFileUploader {
+	return &NotionFileUploader{
+		token:   token,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    http.DefaultClient,
+	}
+}
+
+// Upload implements FileUploader: it creates a file upload and then sends the file content into it
+func (u *NotionFileUploader) Upload(ctx context.Context, name string, content []byte) (string, error) {
+	contentType := cmp.Or(mime.TypeByExtension(path.Ext(name)), "application/octet-stream")
+
+	createBody, err := json.Marshal(map[string]string{"filename": name, "content_type": contentType})
+	if err != nil {
+		return "", err
+	}
+
+	var created struct {
+		ID        string `json:"id"`
+		UploadURL string `json:"upload_url"`
+	}
+	if err := u.do(ctx, u.baseURL+"/v1/file_uploads", "application/json", bytes.NewReader(createBody), &created); err != nil {
+		return "", fmt.Errorf("failed to create file upload for %s: %w", name, err)
+	}
+
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, err := writer.CreatePart(map[string][]string{
+		"Content-Disposition": {fmt.Sprintf(`form-data; name="file"; filename=%q`, name)},
+		"Content-Type":        {contentType},
+	})
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(content); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	uploadURL := cmp.Or(created.UploadURL, u.baseURL+"/v1/file_uploads/"+created.ID+"/send")
+	if err := u.do(ctx, uploadURL, writer.FormDataContentType(), &form, nil); err != nil {
+		return "", fmt.Errorf("failed to send file %s: %w", name, err)
+	}
+
+	return created.ID, nil
+}
+
+func (u *NotionFileUploader) do(ctx context.Context, url, contentType string, body io.Reader, result any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+u.token.String())
+	req.Header.Set("Notion-Version", notionVersion)
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := u.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body) //nolint:errcheck
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, msg)
+	}
+	if result == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// uploadedImageBlock is an image block referencing a file uploaded via Notion's File Upload API
+// (notionapi doesn't support such images yet)
+type uploadedImageBlock struct {
+	nt.BasicBlock
+	Image uploadedImage `json:"image"`
+}
+
+type uploadedImage struct {
+	Type       string `json:"type"`
+	FileUpload struct {
+		ID string `json:"id"`
+	} `json:"file_upload"`
+	Caption []nt.RichText `json:"caption,omitempty"`
+}
+
+func (b uploadedImageBlock) GetRichTextString() string {
+	return ""
+}
+
+func newUploadedImageBlock(uploadID string, caption []nt.RichText) *uploadedImageBlock {
+	block := &uploadedImageBlock{
+		BasicBlock: nt.BasicBlock{
+			Object: nt.ObjectTypeBlock,
+			Type:   nt.BlockTypeImage,
+		},
+		Image: uploadedImage{Type: "file_upload", Caption: caption},
+	}
+	block.Image.FileUpload.ID = uploadID
+
+	return block
+}
+
+// resolveImages makes images with relative paths (that are local files) available in Notion:
+// they are rewritten to the image base URL if it's set, otherwise uploaded via the file uploader (if it's set)
+// Given blocks are not modified
+func (p *Publisher) resolveImages(ctx context.Context, dir string, blocks nt.Blocks) (nt.Blocks, error) {
+	if p.imageBaseURL == nil && p.uploader == nil {
+		return blocks, nil
+	}
+
+	resolved := make(nt.Blocks, len(blocks))
+	for i, block := range blocks {
+		if children := blockChildren(block); len(children) > 0 {
+			resolvedChildren, err := p.resolveImages(ctx, dir, children)
+			if err != nil {
+				return nil, err
+			}
+			block = withBlockChildren(block, resolvedChildren)
+		}
+
+		image, ok := block.(*nt.ImageBlock)
+		if !ok || image.Image.External == nil {
+			resolved[i] = block
+			continue
+		}
+
+		localPath, ok := localImagePath(dir, image.Image.External.URL)
+		if !ok {
+			resolved[i] = block
+			continue
+		}
+
+		if p.imageBaseURL != nil {
+			rewritten := *image
+			rewritten.Image.External = &nt.FileObject{URL: p.imageBaseURL.ResolveReference(&url.URL{Path: localPath}).String()}
+			resolved[i] = &rewritten
+			continue
+		}
+
+		uploadID, err := p.uploadFile(ctx, localPath)
+		if err != nil {
+			return nil, err
+		}
+		if uploadID == "" {
+			resolved[i] = block
+			continue
+		}
+		resolved[i] = newUploadedImageBlock(uploadID, image.Image.Caption)
+	}
+
+	return resolved, nil
+}
+
+// uploadFile uploads the file (relative to the base directory) once, returning the ID of its upload
+// Missing files are reported as warnings (and empty ID is returned): the page is still worth publishing
+func (p *Publisher) uploadFile(ctx context.Context, localPath string) (string, error) {
+	filePath := filepath.Join(p.baseDir, filepath.FromSlash(localPath))
+	if id, ok := p.uploads[filePath]; ok {
+		return id, nil
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		slog.Warn("Couldn't read local image, keeping it as is", "path", localPath, "error", err)
+		return "", nil
+	}
+
+	id, err := p.uploader.Upload(ctx, path.Base(localPath), content)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload %s: %w", localPath, err)
+	}
+	p.uploads[filePath] = id
+
+	return id, nil
+}
+
+// localImagePath returns the slash-separated path (relative to the base directory) of the local file
+// the given image URL points to. Relative URLs are resolved against the given directory, absolute ones against the base directory
+// It returns false for URLs that are not local files (e.g. absolute http or data URLs) or point outside of the base directory
+func localImagePath(dir, rawURL string) (string, bool) {
+	u, ok := localURL(rawURL)
+	if !ok {
+		return "", false
+	}
+
+	localPath := path.Join(dir, u.Path)
+	if strings.HasPrefix(u.Path, "/") {
+		localPath = path.Clean(strings.TrimPrefix(u.Path, "/"))
+	}
+	// Files outside of the base directory are never exposed
+	if localPath == ".." || strings.HasPrefix(localPath, "../") {
+		slog.Warn("Local image is outside of the base directory, keeping it as is", "path", rawURL)
+		return "", false
+	}
+	return localPath, true
+}
+
+// localURL parses the given URL if it points at a local file (it has neither scheme nor host)
+func localURL(rawURL string) (*url.URL, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" {
+		return nil, false
+	}
+	return u, true
+}
+
+// LocalImages returns URLs of images of the given blocks (including nested ones) that point at local files,
+// as they are written in the Markdown source
+func LocalImages(blocks nt.Blocks) []string {
+	images := make([]string, 0)
+	for _, block := range blocks {
+		if image, ok := block.(*nt.ImageBlock); ok && image.Image.External != nil {
+			if _, local := localURL(image.Image.External.URL); local {
+				images = append(images, image.Image.External.URL)
+			}
+		}
+		images = append(images, LocalImages(blockChildren(block))...)
+	}
+	return images
+}
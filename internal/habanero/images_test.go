@@ -0,0 +1,224 @@
+package habanero_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/amberpixels/peppers/internal/habanero"
+	nt "github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFileUploads is a local fake of Notion's File Upload API endpoints
+type fakeFileUploads struct {
+	mu      sync.Mutex
+	created []string          // filenames of created uploads
+	sent    map[string][]byte // uploaded content by upload ID
+}
+
+func newFakeFileUploads(t *testing.T) (*fakeFileUploads, *httptest.Server) {
+	t.Helper()
+
+	fake := &fakeFileUploads{sent: make(map[string][]byte)}
+	mux := http.NewServeMux()
+	var server *httptest.Server
+
+	mux.HandleFunc("POST /v1/file_uploads", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Notion-Version") == "" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			Filename    string `json:"filename"`
+			ContentType string `json:"content_type"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		fake.mu.Lock()
+		fake.created = append(fake.created, req.Filename)
+		id := fmt.Sprintf("upload-%d", len(fake.created))
+		fake.mu.Unlock()
+
+		_ = json.NewEncoder(w).Encode(map[string]string{ //nolint:errcheck
+			"id":         id,
+			"upload_url": server.URL + "/v1/file_uploads/" + id + "/send",
+		})
+	})
+	mux.HandleFunc("POST /v1/file_uploads/{id}/send", func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		content, err := io.ReadAll(file)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		fake.mu.Lock()
+		fake.sent[r.PathValue("id")] = content
+		fake.mu.Unlock()
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": r.PathValue("id"), "status": "uploaded"}) //nolint:errcheck
+	})
+
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return fake, server
+}
+
+func imageBlock(url string, children ...nt.Block) nt.Blocks {
+	image := nt.NewImageBlock(nt.Image{Type: nt.FileTypeExternal, External: &nt.FileObject{URL: url}})
+	if len(children) == 0 {
+		return nt.Blocks{image}
+	}
+	return nt.Blocks{image, nt.NewToggleBlock(nt.Toggle{
+		RichText: []nt.RichText{*nt.NewTextRichText("More")},
+		Children: children,
+	})}
+}
+
+// requestedImages returns JSON of all image objects sent into Notion (including nested ones)
+func requestedImages(t *testing.T, notion *fakeNotion) []string {
+	t.Helper()
+
+	images := make([]string, 0)
+	var collect func(blocks []map[string]any)
+	collect = func(blocks []map[string]any) {
+		for _, block := range blocks {
+			if image, ok := block["image"]; ok {
+				raw, err := json.Marshal(image)
+				require.NoError(t, err)
+				images = append(images, string(raw))
+			}
+			for _, value := range block {
+				content, ok := value.(map[string]any)
+				if !ok || content["children"] == nil {
+					continue
+				}
+				raw, err := json.Marshal(content["children"])
+				require.NoError(t, err)
+				var children []map[string]any
+				require.NoError(t, json.Unmarshal(raw, &children))
+				collect(children)
+			}
+		}
+	}
+
+	for _, req := range notion.requests {
+		raw, err := json.Marshal(req)
+		require.NoError(t, err)
+		var blocks []map[string]any
+		require.NoError(t, json.Unmarshal(raw, &blocks))
+		collect(blocks)
+	}
+
+	return images
+}
+
+func TestPublisher_Create_UploadsLocalImages(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "arch.png"), []byte("png content"), 0o600))
+
+	uploads, server := newFakeFileUploads(t)
+	notion := newFakeNotion()
+	publisher := habanero.NewPublisher(notion.Client(),
+		habanero.WithBaseDir(dir),
+		habanero.WithFileUploader(habanero.NewFileUploader("secret", server.URL)),
+	)
+
+	blocks := imageBlock("docs/arch.png", imageBlock("./docs/arch.png")...)
+	blocks = append(blocks, imageBlock("https://example.com/logo.png")...)
+	blocks = append(blocks, imageBlock("docs/missing.png")...)
+
+	_, err := publisher.Create(context.Background(), nt.Parent{
+		Type:   nt.ParentTypePageID,
+		PageID: "parent",
+	}, &habanero.Page{Properties: titleProps("Architecture"), Blocks: blocks})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"arch.png"}, uploads.created, "same file must be uploaded once")
+	assert.Equal(t, map[string][]byte{"upload-1": []byte("png content")}, uploads.sent)
+	assert.Equal(t, []string{
+		`{"file_upload":{"id":"upload-1"},"type":"file_upload"}`,
+		`{"file_upload":{"id":"upload-1"},"type":"file_upload"}`,
+		`{"external":{"url":"https://example.com/logo.png"},"type":"external"}`,
+		`{"external":{"url":"docs/missing.png"},"type":"external"}`,
+	}, requestedImages(t, notion))
+
+	// Original blocks are kept untouched
+	assert.Equal(t, "docs/arch.png", blocks[0].(*nt.ImageBlock).Image.External.URL)
+}
+
+func TestPublisher_Create_KeepsImagesOutsideBaseDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "site", "docs"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret.png"), []byte("secret"), 0o600))
+
+	uploads, server := newFakeFileUploads(t)
+	notion := newFakeNotion()
+	publisher := habanero.NewPublisher(notion.Client(),
+		habanero.WithBaseDir(filepath.Join(dir, "site")),
+		habanero.WithFileUploader(habanero.NewFileUploader("secret", server.URL)),
+	)
+
+	blocks := imageBlock("../../secret.png", imageBlock("/../secret.png")...)
+
+	_, err := publisher.Create(context.Background(), nt.Parent{
+		Type:   nt.ParentTypePageID,
+		PageID: "parent",
+	}, &habanero.Page{Properties: titleProps("Secrets"), Dir: "docs", Blocks: blocks})
+	require.NoError(t, err)
+
+	assert.Empty(t, uploads.created)
+	assert.Equal(t, []string{
+		`{"external":{"url":"../../secret.png"},"type":"external"}`,
+		`{"external":{"url":"/../secret.png"},"type":"external"}`,
+	}, requestedImages(t, notion))
+}
+
+func TestPublisher_Create_RewritesLocalImages(t *testing.T) {
+	baseURL, err := url.Parse("https://raw.githubusercontent.com/amberpixels/peppers/main")
+	require.NoError(t, err)
+
+	notion := newFakeNotion()
+	publisher := habanero.NewPublisher(notion.Client(), habanero.WithImageBaseURL(baseURL))
+
+	blocks := imageBlock("docs/arch.png", imageBlock("/assets/logo.svg")...)
+	blocks = append(blocks, imageBlock("data:image/png;base64,AAAA")...)
+
+	_, err = publisher.Create(context.Background(), nt.Parent{
+		Type:   nt.ParentTypePageID,
+		PageID: "parent",
+	}, &habanero.Page{Properties: titleProps("Architecture"), Blocks: blocks})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`{"external":{"url":"https://raw.githubusercontent.com/amberpixels/peppers/main/docs/arch.png"},"type":"external"}`,
+		`{"external":{"url":"https://raw.githubusercontent.com/amberpixels/peppers/main/assets/logo.svg"},"type":"external"}`,
+		`{"external":{"url":"data:image/png;base64,AAAA"},"type":"external"}`,
+	}, requestedImages(t, notion))
+}
+
+func TestLocalImages(t *testing.T) {
+	blocks := imageBlock("docs/arch.png", imageBlock("https://example.com/logo.svg", imageBlock("/assets/logo.svg")...)...)
+
+	assert.Equal(t, []string{"docs/arch.png", "/assets/logo.svg"}, habanero.LocalImages(blocks))
+	assert.Empty(t, habanero.LocalImages(nil))
+}
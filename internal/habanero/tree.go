@@ -0,0 +1,108 @@
+package habanero
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	nt "github.com/jomei/notionapi"
+)
+
+// PageTree is a hierarchy of pages published together (e.g. a directory of Markdown files):
+// the page itself and its subpages
+type PageTree struct {
+	Page     *Page
+	Children []*PageTree
+
+	// PageID is the previously published page of the tree node (e.g. taken from the front matter)
+	// It's reused instead of looking the page up by its title, so renamed documents keep their pages
+	PageID nt.PageID
+
+	// Published is the Notion page of the tree node, it's set by PrepareTree
+	Published *nt.Page
+}
+
+// Walk calls the given function for the tree node and all its descendants (parents go first)
+func (t *PageTree) Walk(fn func(node *PageTree) error) error {
+	if err := fn(t); err != nil {
+		return err
+	}
+	for _, child := range t.Children {
+		if err := child.Walk(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrepareTree makes sure every page of the tree exists under the given parent page, without publishing their content:
+// so URLs of all the pages are known before the content (that links to each other) is published by SyncTree
+// If reuse is set, pages previously published (with the given IDs or the same titles) are taken instead of creating new ones
+func (p *Publisher) PrepareTree(ctx context.Context, parentID nt.PageID, tree *PageTree, reuse bool) error {
+	title := PageTitle(tree.Page.Properties)
+
+	if reuse {
+		pageID := tree.PageID
+		var err error
+		if pageID == "" {
+			pageID, err = p.FindChildPage(ctx, parentID, title)
+		}
+		switch {
+		case err == nil:
+			if tree.Published, err = p.client.Page.Get(ctx, pageID); err != nil {
+				return fmt.Errorf("failed to get page %q: %w", title, err)
+			}
+		case errors.Is(err, ErrPageNotFound):
+			// Renamed documents get new pages, as pages are matched by titles
+			slog.Warn("No previously published page found, creating a new one", "title", title)
+		default:
+			return err
+		}
+	}
+
+	if tree.Published == nil {
+		// Subpages are shown as blocks of the page in order of creation, while the content is published afterward:
+		// it takes the place of the placeholder created before subpages (see Sync)
+		var placeholder nt.Blocks
+		if len(tree.Children) > 0 {
+			placeholder = nt.Blocks{nt.NewParagraphBlock(nt.Paragraph{RichText: []nt.RichText{}})}
+		}
+		created, err := p.Create(ctx, nt.Parent{Type: nt.ParentTypePageID, PageID: parentID}, &Page{
+			Properties: tree.Page.Properties,
+			Icon:       tree.Page.Icon,
+			Cover:      tree.Page.Cover,
+			Blocks:     placeholder,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create page %q: %w", title, err)
+		}
+		tree.Published = created
+	}
+
+	for _, child := range tree.Children {
+		if err := p.PrepareTree(ctx, nt.PageID(tree.Published.ID), child, reuse); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SyncTree publishes content of all the pages of the tree previously prepared by PrepareTree
+// Subpages are kept in place, as Sync never removes nested pages
+func (p *Publisher) SyncTree(ctx context.Context, tree *PageTree) error {
+	return tree.Walk(func(node *PageTree) error {
+		if node.Published == nil {
+			return fmt.Errorf("page %q is not prepared", PageTitle(node.Page.Properties))
+		}
+
+		synced, err := p.Sync(ctx, nt.PageID(node.Published.ID), node.Page)
+		if err != nil {
+			return fmt.Errorf("failed to sync page %q: %w", PageTitle(node.Page.Properties), err)
+		}
+		node.Published = synced
+
+		return nil
+	})
+}
@@ -0,0 +1,89 @@
+package habanero_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amberpixels/peppers/internal/habanero"
+	nt "github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func docsTree() *habanero.PageTree {
+	return &habanero.PageTree{
+		Page: &habanero.Page{Properties: titleProps("Docs"), Blocks: nt.Blocks{paragraph("Welcome")}},
+		Children: []*habanero.PageTree{
+			{Page: &habanero.Page{Properties: titleProps("Setup"), Blocks: nt.Blocks{paragraph("Install it")}}},
+			{
+				Page: &habanero.Page{Properties: titleProps("Guides")},
+				Children: []*habanero.PageTree{
+					{Page: &habanero.Page{Properties: titleProps("Intro"), Blocks: nt.Blocks{paragraph("Hello")}}},
+				},
+			},
+		},
+	}
+}
+
+func TestPublisher_PublishTree(t *testing.T) {
+	notion := newFakeNotion()
+	publisher := habanero.NewPublisher(notion.Client())
+	parentID := nt.PageID("parent")
+
+	tree := docsTree()
+	require.NoError(t, publisher.PrepareTree(context.Background(), parentID, tree, false))
+
+	// All pages exist (with URLs known) before any content is published
+	urls := make(map[string]string)
+	require.NoError(t, tree.Walk(func(node *habanero.PageTree) error {
+		require.NotNil(t, node.Published)
+		urls[habanero.PageTitle(node.Page.Properties)] = node.Published.URL
+		return nil
+	}))
+	assert.Len(t, urls, 4)
+	assert.Equal(t, []string{"paragraph: ", "child_page: ", "child_page: "},
+		outline(t, notion.children[nt.BlockID(tree.Published.ID)], nil), "content must not be published yet")
+
+	require.NoError(t, publisher.SyncTree(context.Background(), tree))
+
+	assert.Equal(t, []string{"child_page: "}, outline(t, notion.children[nt.BlockID(parentID)], nil))
+	// Content goes above subpages
+	assert.Equal(t, []string{
+		"paragraph: Welcome",
+		"child_page: ",
+		"child_page: ",
+	}, outline(t, notion.children[nt.BlockID(tree.Published.ID)], nil))
+
+	guides := tree.Children[1]
+	assert.Equal(t, []string{"child_page: "}, outline(t, notion.children[nt.BlockID(guides.Published.ID)], nil))
+	intro := guides.Children[0]
+	assert.Equal(t, []string{"paragraph: Hello"}, outline(t, notion.children[nt.BlockID(intro.Published.ID)], nil))
+
+	// Publishing again with reuse keeps the same pages
+	pagesCount := len(notion.pages)
+	again := docsTree()
+	again.Children[0].Page.Blocks = nt.Blocks{paragraph("Install it faster")}
+	require.NoError(t, publisher.PrepareTree(context.Background(), parentID, again, true))
+	require.NoError(t, publisher.SyncTree(context.Background(), again))
+
+	assert.Len(t, notion.pages, pagesCount)
+	assert.Equal(t, tree.Children[0].Published.ID, again.Children[0].Published.ID)
+	assert.Equal(t, []string{"paragraph: Install it faster"},
+		outline(t, notion.children[nt.BlockID(again.Children[0].Published.ID)], nil))
+	assert.Equal(t, []string{
+		"paragraph: Welcome",
+		"child_page: ",
+		"child_page: ",
+	}, outline(t, notion.children[nt.BlockID(again.Published.ID)], nil))
+
+	// Renamed pages are found by their IDs
+	renamed := docsTree()
+	renamed.Children[0].Page.Properties = titleProps("Installation")
+	renamed.Children[0].PageID = nt.PageID(tree.Children[0].Published.ID)
+	require.NoError(t, publisher.PrepareTree(context.Background(), parentID, renamed, true))
+	require.NoError(t, publisher.SyncTree(context.Background(), renamed))
+
+	assert.Len(t, notion.pages, pagesCount)
+	assert.Equal(t, tree.Children[0].Published.ID, renamed.Children[0].Published.ID)
+	assert.Equal(t, "Installation", habanero.PageTitle(renamed.Children[0].Published.Properties))
+}
@@ -0,0 +1,245 @@
+package habanero
+
+import (
+	"context"
+	"fmt"
+
+	nt "github.com/jomei/notionapi"
+)
+
+const (
+	// MaxChildrenPerRequest is the maximum number of elements of any children array in a single Notion API request
+	MaxChildrenPerRequest = 100
+
+	// MaxBlocksPerRequest is the maximum number of blocks (including nested ones) in a single Notion API request
+	MaxBlocksPerRequest = 1000
+)
+
+// pendingBlock is a block that is ready to be sent in a single request
+// with its children that must be appended in follow-up requests (once the block has its ID)
+type pendingBlock struct {
+	block    nt.Block
+	deferred nt.Blocks
+}
+
+// size returns the number of blocks the pending block will take in a request
+func (pb pendingBlock) size() int {
+	return 1 + len(blockChildren(pb.block))
+}
+
+// prepareBlocks makes given blocks satisfy Notion API limits:
+// A block is sent together with its children only when it makes two levels of nesting max
+// (so its children don't have children of their own) and there are not too many of them.
+// Otherwise, children are detached and deferred to be appended separately.
+func prepareBlocks(blocks nt.Blocks) []pendingBlock {
+	result := make([]pendingBlock, 0, len(blocks))
+	for _, block := range blocks {
+		children := blockChildren(block)
+		if len(children) == 0 {
+			result = append(result, pendingBlock{block: block})
+			continue
+		}
+
+		// Table rows can't be appended to an empty table, so we keep as many of them as possible
+		if block.GetType() == nt.BlockTypeTable {
+			if len(children) <= MaxChildrenPerRequest {
+				result = append(result, pendingBlock{block: block})
+			} else {
+				result = append(result, pendingBlock{
+					block:    withBlockChildren(block, children[:MaxChildrenPerRequest]),
+					deferred: children[MaxChildrenPerRequest:],
+				})
+			}
+			continue
+		}
+
+		if len(children) <= MaxChildrenPerRequest && !anyHasChildren(children) {
+			result = append(result, pendingBlock{block: block})
+			continue
+		}
+
+		result = append(result, pendingBlock{
+			block:    withBlockChildren(block, nil),
+			deferred: children,
+		})
+	}
+
+	return result
+}
+
+// batchBlocks splits pending blocks into batches each of them fits into a single request
+func batchBlocks(pending []pendingBlock) [][]pendingBlock {
+	batches := make([][]pendingBlock, 0)
+
+	var batch []pendingBlock
+	var batchSize int
+	for _, pb := range pending {
+		if len(batch) == MaxChildrenPerRequest || (len(batch) > 0 && batchSize+pb.size() > MaxBlocksPerRequest) {
+			batches = append(batches, batch)
+			batch, batchSize = nil, 0
+		}
+		batch = append(batch, pb)
+		batchSize += pb.size()
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+
+	return batches
+}
+
+// appendBlocks appends given blocks to the given parent block (or page) keeping their order.
+// Blocks are split into as many requests as required by Notion API limits
+func (p *Publisher) appendBlocks(ctx context.Context, parentID nt.BlockID, blocks nt.Blocks) error {
+	return p.appendPending(ctx, parentID, "", prepareBlocks(blocks))
+}
+
+// appendPending appends given blocks to the given parent block right after the given child block
+// (or to the bottom of the parent if it's empty)
+func (p *Publisher) appendPending(ctx context.Context, parentID, after nt.BlockID, pending []pendingBlock) error {
+	for _, batch := range batchBlocks(pending) {
+		children := make(nt.Blocks, len(batch))
+		for i, pb := range batch {
+			children[i] = pb.block
+		}
+
+		resp, err := p.client.Block.AppendChildren(ctx, parentID, &nt.AppendBlockChildrenRequest{
+			After:    after,
+			Children: children,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to append children to %s: %w", parentID, err)
+		}
+		if len(resp.Results) != len(children) {
+			return fmt.Errorf("failed to append children to %s: expected %d created blocks, got %d",
+				parentID, len(children), len(resp.Results))
+		}
+		// Next batches go after this one
+		if after != "" {
+			after = resp.Results[len(resp.Results)-1].GetID()
+		}
+
+		for i, pb := range batch {
+			if len(pb.deferred) == 0 {
+				continue
+			}
+			if err := p.appendBlocks(ctx, resp.Results[i].GetID(), pb.deferred); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// splitFirstBatch returns the leading blocks that can be sent right in the page creation request
+// (Page creation doesn't return IDs of created blocks, so no deferred children are allowed there)
+// and the rest of blocks that must be appended afterward
+func splitFirstBatch(pending []pendingBlock) (nt.Blocks, []pendingBlock) {
+	first := make(nt.Blocks, 0)
+
+	batches := batchBlocks(pending)
+	if len(batches) == 0 {
+		return first, nil
+	}
+	for _, pb := range batches[0] {
+		if len(pb.deferred) > 0 {
+			break
+		}
+		first = append(first, pb.block)
+	}
+
+	return first, pending[len(first):]
+}
+
+func anyHasChildren(blocks nt.Blocks) bool {
+	for _, b := range blocks {
+		if len(blockChildren(b)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// blockChildren returns nested children of the given block (if block supports them)
+func blockChildren(block nt.Block) nt.Blocks {
+	switch v := block.(type) {
+	case *nt.ParagraphBlock:
+		return v.Paragraph.Children
+	case *nt.Heading1Block:
+		return v.Heading1.Children
+	case *nt.Heading2Block:
+		return v.Heading2.Children
+	case *nt.Heading3Block:
+		return v.Heading3.Children
+	case *nt.CalloutBlock:
+		return v.Callout.Children
+	case *nt.QuoteBlock:
+		return v.Quote.Children
+	case *nt.TableBlock:
+		return v.Table.Children
+	case *nt.BulletedListItemBlock:
+		return v.BulletedListItem.Children
+	case *nt.NumberedListItemBlock:
+		return v.NumberedListItem.Children
+	case *nt.ToDoBlock:
+		return v.ToDo.Children
+	case *nt.ToggleBlock:
+		return v.Toggle.Children
+	default:
+		return nil
+	}
+}
+
+// withBlockChildren returns a copy of the given block with its children replaced with the given ones
+// Given block is not modified
+func withBlockChildren(block nt.Block, children nt.Blocks) nt.Block {
+	switch v := block.(type) {
+	case *nt.ParagraphBlock:
+		c := *v
+		c.Paragraph.Children = children
+		return &c
+	case *nt.Heading1Block:
+		c := *v
+		c.Heading1.Children = children
+		return &c
+	case *nt.Heading2Block:
+		c := *v
+		c.Heading2.Children = children
+		return &c
+	case *nt.Heading3Block:
+		c := *v
+		c.Heading3.Children = children
+		return &c
+	case *nt.CalloutBlock:
+		c := *v
+		c.Callout.Children = children
+		return &c
+	case *nt.QuoteBlock:
+		c := *v
+		c.Quote.Children = children
+		return &c
+	case *nt.TableBlock:
+		c := *v
+		c.Table.Children = children
+		return &c
+	case *nt.BulletedListItemBlock:
+		c := *v
+		c.BulletedListItem.Children = children
+		return &c
+	case *nt.NumberedListItemBlock:
+		c := *v
+		c.NumberedListItem.Children = children
+		return &c
+	case *nt.ToDoBlock:
+		c := *v
+		c.ToDo.Children = children
+		return &c
+	case *nt.ToggleBlock:
+		c := *v
+		c.Toggle.Children = children
+		return &c
+	default:
+		return block
+	}
+}
@@ -0,0 +1,217 @@
+package serrano
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	nt "github.com/jomei/notionapi"
+)
+
+// hardBreak is a Markdown hard line break standing for new lines inside rich texts
+const hardBreak = "\\\n"
+
+// inlineEscaper escapes characters starting inline Markdown constructs (emphasis, code spans, links, HTML tags)
+var inlineEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `~`, `\~`, `[`, `\[`, `]`, `\]`, `<`, `\<`,
+)
+
+// blockMarkerRe matches markers that start blocks (headings, quotes, lists, breaks) when they open a line
+var blockMarkerRe = regexp.MustCompile(`^(#{1,6}|[>+-]|-{2,}|={2,}|\d{1,9}[.)])([ \t]|$)`)
+
+// renderRichTexts renders rich texts into inline Markdown, new lines are replaced with the given line break
+// Emphasis shared by neighbour rich texts is kept open across them (e.g. `**bold *both* bold**`)
+func renderRichTexts(richTexts []nt.RichText, lineBreak string) string {
+	var sb strings.Builder
+
+	var open []string  // emphasis markers currently opened, outermost first
+	var pending string // trailing whitespace of the previous rich text, it must stay outside of closed emphasis
+	for _, rt := range mergeRichTexts(richTexts) {
+		content := richTextContent(rt)
+		if rt.Equation == nil && rt.Mention == nil && strings.TrimSpace(content) == "" {
+			pending += strings.ReplaceAll(content, "\n", lineBreak)
+			continue
+		}
+
+		// Block markers are recognized after indentation too
+		indented := strings.TrimRight(pending, " \t")
+		lineStart := sb.Len() == 0 && indented == "" || strings.HasSuffix(indented, "\n")
+		leading, core, trailing := renderRichText(rt, lineBreak, lineStart)
+		markers := emphasisMarkers(annotationsOf(rt))
+
+		// Close markers that are not needed anymore (with all markers opened after them)
+		for i, marker := range open {
+			if !slices.Contains(markers, marker) {
+				for j := len(open) - 1; j >= i; j-- {
+					sb.WriteString(open[j])
+				}
+				open = open[:i]
+				break
+			}
+		}
+		sb.WriteString(pending)
+
+		opening := make([]string, 0, len(markers))
+		for _, marker := range markers {
+			if !slices.Contains(open, marker) {
+				opening = append(opening, marker)
+			}
+		}
+		// Markdown emphasis can't start with whitespace, so it's moved outside
+		sb.WriteString(leading)
+		for _, marker := range opening {
+			sb.WriteString(marker)
+		}
+		open = append(open, opening...)
+
+		sb.WriteString(core)
+		pending = trailing
+	}
+
+	for j := len(open) - 1; j >= 0; j-- {
+		sb.WriteString(open[j])
+	}
+	sb.WriteString(pending)
+
+	return sb.String()
+}
+
+// emphasisMarkers returns Markdown emphasis markers for the given annotations, in the order of opening
+func emphasisMarkers(annotations nt.Annotations) []string {
+	markers := make([]string, 0, 3)
+	if annotations.Bold {
+		markers = append(markers, "**")
+	}
+	if annotations.Italic {
+		markers = append(markers, "*")
+	}
+	if annotations.Strikethrough {
+		markers = append(markers, "~~")
+	}
+	return markers
+}
+
+// mergeRichTexts merges neighbour rich texts having the same annotations and links
+// (Notion splits long texts, so otherwise e.g. `**a****b**` would be rendered)
+func mergeRichTexts(richTexts []nt.RichText) []nt.RichText {
+	merged := make([]nt.RichText, 0, len(richTexts))
+	for _, rt := range richTexts {
+		if n := len(merged); n > 0 && canMerge(merged[n-1], rt) {
+			last := merged[n-1]
+			content := richTextContent(last) + richTextContent(rt)
+			last.Text = &nt.Text{Content: content, Link: last.Text.Link}
+			last.PlainText = content
+			merged[n-1] = last
+			continue
+		}
+		merged = append(merged, rt)
+	}
+	return merged
+}
+
+func canMerge(a, b nt.RichText) bool {
+	if a.Text == nil || b.Text == nil {
+		return false
+	}
+	return annotationsOf(a) == annotationsOf(b) && linkOf(a) == linkOf(b)
+}
+
+// renderRichText renders a rich text without its emphasis (see renderRichTexts)
+// Surrounding whitespace is returned separately, as it must be kept outside of the emphasis
+// If lineStart is set, the rich text opens a line, so block markers at its beginning are escaped as well
+func renderRichText(rt nt.RichText, lineBreak string, lineStart bool) (leading, core, trailing string) {
+	switch {
+	case rt.Equation != nil:
+		return "", "$" + rt.Equation.Expression + "$", ""
+	case rt.Mention != nil:
+		return "", escapeMarkdown(rt.PlainText, lineStart), ""
+	}
+
+	content := richTextContent(rt)
+	core = strings.TrimSpace(content)
+	leading = content[:strings.Index(content, core)]
+	trailing = content[len(leading)+len(core):]
+
+	annotations := annotationsOf(rt)
+	if annotations.Code {
+		core = codeSpan(core)
+	} else {
+		core = escapeMarkdown(core, lineStart || strings.Contains(leading, "\n"))
+		core = strings.ReplaceAll(core, "\n", lineBreak)
+	}
+
+	if link := linkOf(rt); link != "" {
+		core = "[" + core + "](" + link + ")"
+	}
+	// Same HTML tags as jalapeno converts into these annotations
+	if annotations.Underline {
+		core = "<u>" + core + "</u>"
+	}
+	if annotations.Color == nt.ColorYellowBackground {
+		core = "<mark>" + core + "</mark>"
+	}
+
+	return strings.ReplaceAll(leading, "\n", lineBreak), core, strings.ReplaceAll(trailing, "\n", lineBreak)
+}
+
+// escapeMarkdown escapes characters of the given plain text that Markdown would take for formatting
+// Block markers are escaped only at beginnings of lines (the text's own beginning too, if it starts a line)
+func escapeMarkdown(s string, lineStart bool) string {
+	lines := strings.Split(inlineEscaper.Replace(s), "\n")
+	for i, line := range lines {
+		if i == 0 && !lineStart {
+			continue
+		}
+		if m := blockMarkerRe.FindStringSubmatchIndex(line); m != nil {
+			// Ordered list markers are escaped by their delimiter, as digits are not escapable
+			at := m[2]
+			if line[0] >= '0' && line[0] <= '9' {
+				at = m[3] - 1
+			}
+			lines[i] = line[:at] + `\` + line[at:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// codeSpan wraps the given text into backticks (enough to keep backticks inside)
+func codeSpan(s string) string {
+	ticks := "`"
+	for strings.Contains(s, ticks) {
+		ticks += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return ticks + s + ticks
+}
+
+func richTextContent(rt nt.RichText) string {
+	if rt.Text != nil {
+		return rt.Text.Content
+	}
+	return rt.PlainText
+}
+
+func annotationsOf(rt nt.RichText) nt.Annotations {
+	if rt.Annotations == nil {
+		return nt.Annotations{}
+	}
+	return *rt.Annotations
+}
+
+func linkOf(rt nt.RichText) string {
+	if rt.Text != nil && rt.Text.Link != nil {
+		return rt.Text.Link.Url
+	}
+	return rt.Href
+}
+
+// plainText returns the text of rich texts without any formatting
+func plainText(richTexts []nt.RichText) string {
+	var sb strings.Builder
+	for _, rt := range richTexts {
+		sb.WriteString(richTextContent(rt))
+	}
+	return sb.String()
+}
@@ -0,0 +1,129 @@
+package serrano_test
+
+import (
+	"testing"
+
+	"github.com/amberpixels/peppers/internal/serrano"
+	"github.com/amberpixels/peppers/internal/testhelpers"
+	"github.com/amberpixels/peppers/pkg/jalapeno"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parserInstance is configured the same way as pprs does
+var parserInstance = jalapeno.NewParser(nil)
+
+// TestRoundTrip checks that Markdown -> Notion -> Markdown -> Notion conversion gives the same blocks,
+// i.e. pulling a published page and publishing it again changes nothing
+// Constructs that can't survive the round trip are listed explicitly with the reason of the loss:
+// the test fails if they become stable, so they can be moved into stable ones
+func TestRoundTrip(t *testing.T) {
+	type AssertFunc = func(t *testing.T, source string, lossy string)
+	type TestFunc = func(name string, source string, lossy string)
+
+	f, ff, xf, run := testhelpers.GenerateCases[TestFunc, AssertFunc](t, func(t *testing.T, source string, lossy string) {
+		assertRoundTrip(t, parserInstance, source, lossy)
+	})
+	_, _, _ = f, ff, xf
+
+	// --- Stable constructs ---
+
+	f("Headings", "# One\n\n## Two\n\n### Three", "")
+	f("Heading level 4", "#### Four", "") // becomes H3 in Notion, stable afterwards
+	f("Paragraphs", "First paragraph\n\nSecond paragraph", "")
+	f("Emphasis", "Some **bold**, *italic*, ***both***, ~~strike~~ and `code`", "")
+	f("Nested emphasis", "**bold with *italic* inside**", "")
+	f("Overlapping emphasis", "*italic **both*** and **bold ~~struck~~**", "")
+	f("Links", "[Site](https://example.com) and <https://example.org>", "")
+	f("Formatted link", "**[bold link](https://example.com)**", "")
+	f("Inline HTML formatting", "<u>under</u> and <mark>marked</mark>", "")
+	f("Bulleted list", "- One\n- Two\n- Three", "")
+	f("Numbered list", "1. One\n2. Two\n3. Three", "")
+	f("Nested lists", "- One\n  - Nested\n    1. Deep\n- Two", "")
+	f("Task list", "- [x] Done\n- [ ] Todo", "")
+	f("Code block", "```go\nfunc main() {}\n```", "")
+	f("Code block without language", "```\nplain\n```", "")
+	f("Code block with fences inside", "````md\n```go\nx := 1\n```\n````", "")
+	f("Blockquote", "> Quoted **text**", "")
+	f("GitHub alert", "> [!WARNING]\n> Be careful", "")
+	f("Divider", "Before\n\n---\n\nAfter", "")
+	f("Image", "![Diagram](https://example.com/diagram.png)", "")
+	f("Table", "| Name | Value |\n| --- | --- |\n| a | `b` |\n| **c** | [d](https://example.com) |", "")
+	f("Details", "<details>\n<summary>More</summary>\n\nHidden **content**\n\n</details>", "")
+	f("Escaped inline formatting", `Not \*emphasis\*, \_under\_ or \~~strike\~~, \[x\](y), \<b>, \`+"`tick\\`"+` and a \\ backslash`, "")
+	f("Escaped block markers", "\\# Not a heading\n\n\\> Not a quote\n\n\\- Not a list\n\n1968\\. Not a list\n\n\\---", "")
+	f("Escapes inside formatting", "**\\# bold \\*star\\*** and [\\[link\\]](https://example.com)", "")
+	f("No escapes in code", "`*not* [escaped] \\`", "")
+	f("Escaped image alt", "![\\[x\\] \\*y\\*](https://example.com/x.png)", "")
+	f("Mixed document", `# Project
+
+Intro with [link](https://example.com).
+
+## Install
+
+1. Download
+2. Run:
+
+   `+"```sh\n   make install\n   ```"+`
+
+> [!NOTE]
+> Requires Go
+
+- [ ] Write docs`, "")
+
+	// --- Lossy constructs ---
+
+	f("Hard line break", "First line\\\nSecond line", "jalapeno drops hard line breaks inside paragraphs")
+	f("Soft line break", "First line\nSecond line", "jalapeno drops soft line breaks inside paragraphs")
+	f("Footnotes", "Text[^1]\n\n[^1]: Note", "footnotes come back as superscript text, a divider, a heading and a list")
+	f("Definition lists", "Term\n: Definition", "definition lists come back as ordinary bold and plain paragraphs")
+	f("HTML tables", "<table>\n<tr><td>a</td><td>b</td></tr>\n</table>", "HTML tables without header cells come back as GFM tables with a header row")
+
+	run()
+}
+
+// TestRoundTrip_ParserOptions checks the round trip of constructs that need parser options to appear
+func TestRoundTrip_ParserOptions(t *testing.T) {
+	type AssertFunc = func(t *testing.T, source string, lossy string, opt jalapeno.ParserOption)
+	type TestFunc = func(name string, source string, lossy string, opt jalapeno.ParserOption)
+
+	f, ff, xf, run := testhelpers.GenerateCases[TestFunc, AssertFunc](t, func(t *testing.T, source string, lossy string, opt jalapeno.ParserOption) {
+		assertRoundTrip(t, jalapeno.NewParser(nil, opt), source, lossy)
+	})
+	_, _, _ = f, ff, xf
+
+	// --- Stable constructs ---
+
+	f("Toggle headings of a single level", "## Section\n\nContent", "", jalapeno.WithToggleHeadings(2))
+
+	// --- Lossy constructs ---
+
+	f("Toggle headings", "### Section\n\n#### Subsection\n\nContent",
+		"H4-H6 become H3 headings, so their sections come back as siblings of the toggle heading instead of its content",
+		jalapeno.WithToggleHeadings(3))
+	f("Non-alert callouts", "Text[^1]\n\n[^1]: Note", "callouts without GitHub alert emojis come back as quotes",
+		jalapeno.WithFootnoteStyle(jalapeno.FootnoteStyleCallout))
+
+	run()
+}
+
+// assertRoundTrip converts the Markdown source with the given parser, renders it back and converts it again
+// Lossy constructs must change (lossy is the reason of the loss), others must stay the same
+func assertRoundTrip(t *testing.T, parser *jalapeno.Parser, source string, lossy string) {
+	t.Helper()
+
+	blocks, _, err := parser.ParseBlocks([]byte(source))
+	require.NoError(t, err, "Parsing failed")
+
+	markdown := serrano.Render(blocks)
+	roundTripped, _, err := parser.ParseBlocks([]byte(markdown))
+	require.NoError(t, err, "Parsing of rendered Markdown failed")
+
+	if lossy != "" {
+		assert.NotEqual(t, blocks, roundTripped, "Construct is not lossy anymore (%s), move it into stable ones", lossy)
+		return
+	}
+
+	assert.Equal(t, blocks, roundTripped, "Blocks changed after the round trip. Rendered Markdown:\n%s", markdown)
+	assert.Equal(t, markdown, serrano.Render(roundTripped), "Rendered Markdown is not stable")
+}
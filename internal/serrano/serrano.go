@@ -0,0 +1,304 @@
+// Package serrano is a library that provides Notion -> Markdown conversion (the reverse of jalapeno)
+package serrano
+
+import (
+	"cmp"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	nt "github.com/jomei/notionapi"
+)
+
+// RenderPage renders a Notion page into GFM Markdown: the title becomes the leading H1 heading
+// (as jalapeno takes the first H1 heading as the page title), followed by the page blocks
+// localImages are paths of local images of the Markdown file the page was published from (see habanero.LocalImages):
+// images hosted by Notion (i.e. uploaded local images) are rendered with the paths of the same files
+func RenderPage(title string, blocks nt.Blocks, localImages []string) string {
+	r := &renderer{localImages: slices.Clone(localImages)}
+	if title == "" {
+		return r.render(blocks)
+	}
+	return joinBlocks("# "+title, r.renderBlocks(blocks)) + "\n"
+}
+
+// Render renders the given Notion blocks into GFM Markdown
+// Characters of plain text that Markdown would take for formatting are escaped with backslashes (code is kept as is)
+// Blocks that have no Markdown representation are rendered as HTML comments
+func Render(blocks nt.Blocks) string {
+	return (&renderer{}).render(blocks)
+}
+
+// renderer renders Notion blocks, it keeps local images not matched with images hosted by Notion yet
+type renderer struct {
+	localImages []string
+}
+
+func (r *renderer) render(blocks nt.Blocks) string {
+	rendered := r.renderBlocks(blocks)
+	if rendered == "" {
+		return ""
+	}
+	return rendered + "\n"
+}
+
+// renderBlocks renders blocks separated by blank lines (list items of the same list are kept tight)
+func (r *renderer) renderBlocks(blocks nt.Blocks) string {
+	var sb strings.Builder
+
+	var prev nt.Block
+	number := 0
+	for _, block := range blocks {
+		if block.GetType() == nt.BlockTypeNumberedListItem {
+			number++
+		} else {
+			number = 0
+		}
+
+		rendered := r.renderBlock(block, number)
+		if rendered == "" {
+			continue
+		}
+
+		if prev != nil {
+			if isListItem(prev) && isListItem(block) {
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString("\n\n")
+			}
+		}
+		sb.WriteString(rendered)
+		prev = block
+	}
+
+	return sb.String()
+}
+
+func (r *renderer) renderBlock(block nt.Block, number int) string {
+	switch v := block.(type) {
+	case *nt.ParagraphBlock:
+		return joinBlocks(renderRichTexts(v.Paragraph.RichText, hardBreak), r.renderBlocks(v.Paragraph.Children))
+	case *nt.Heading1Block:
+		return r.renderHeading(1, v.Heading1)
+	case *nt.Heading2Block:
+		return r.renderHeading(2, v.Heading2)
+	case *nt.Heading3Block:
+		return r.renderHeading(3, v.Heading3)
+	case *nt.BulletedListItemBlock:
+		return r.renderListItem("- ", v.BulletedListItem.RichText, v.BulletedListItem.Children)
+	case *nt.NumberedListItemBlock:
+		return r.renderListItem(fmt.Sprintf("%d. ", number), v.NumberedListItem.RichText, v.NumberedListItem.Children)
+	case *nt.ToDoBlock:
+		marker := "- [ ] "
+		if v.ToDo.Checked {
+			marker = "- [x] "
+		}
+		return r.renderListItem(marker, v.ToDo.RichText, v.ToDo.Children)
+	case *nt.QuoteBlock:
+		return prefixLines("> ", joinBlocks(renderRichTexts(v.Quote.RichText, hardBreak), r.renderBlocks(v.Quote.Children)))
+	case *nt.CalloutBlock:
+		return r.renderCallout(v)
+	case *nt.ToggleBlock:
+		// Same as jalapeno converts `<details>` into toggles
+		summary := html.EscapeString(plainText(v.Toggle.RichText))
+		return joinBlocks("<details>\n<summary>"+summary+"</summary>", r.renderBlocks(v.Toggle.Children), "</details>")
+	case *nt.CodeBlock:
+		return renderCode(v.Code)
+	case *nt.ImageBlock:
+		return r.renderImage(v.Image)
+	case *nt.DividerBlock:
+		return "---"
+	case *nt.TableBlock:
+		return renderTable(v)
+	case *nt.EquationBlock:
+		return "$$\n" + v.Equation.Expression + "\n$$"
+	case *nt.BookmarkBlock:
+		return "<" + v.Bookmark.URL + ">"
+	case *nt.EmbedBlock:
+		return "<" + v.Embed.URL + ">"
+	case *nt.LinkPreviewBlock:
+		return "<" + v.LinkPreview.URL + ">"
+	case *nt.ChildPageBlock, *nt.ChildDatabaseBlock:
+		// Nested pages and databases are separate documents
+		return ""
+	default:
+		return fmt.Sprintf("<!-- unsupported Notion block: %s -->", block.GetType())
+	}
+}
+
+func (r *renderer) renderHeading(level int, heading nt.Heading) string {
+	// Children can be only inside toggleable headings: their section content
+	return joinBlocks(strings.Repeat("#", level)+" "+renderRichTexts(heading.RichText, " "), r.renderBlocks(heading.Children))
+}
+
+// renderListItem renders a list item with the given marker: its nested blocks are indented to the item content
+func (r *renderer) renderListItem(marker string, richTexts []nt.RichText, children nt.Blocks) string {
+	indent := strings.Repeat(" ", len(marker))
+	item := marker + indentLines(indent, renderRichTexts(richTexts, hardBreak), false)
+
+	if len(children) == 0 {
+		return item
+	}
+
+	// Nested lists are kept tight, other nested blocks need to be separated by a blank line
+	separator := "\n\n"
+	if isListItem(children[0]) {
+		separator = "\n"
+	}
+	return item + separator + indentLines(indent, r.renderBlocks(children), true)
+}
+
+// githubAlerts maps emojis of callouts created by jalapeno from GitHub-style alerts back into alert types
+var githubAlerts = map[string]string{
+	"ℹ️": "NOTE",
+	"💡":  "TIP",
+	"❗":  "IMPORTANT",
+	"⚠️": "WARNING",
+	"🛑":  "CAUTION",
+}
+
+func (r *renderer) renderCallout(callout *nt.CalloutBlock) string {
+	text := renderRichTexts(callout.Callout.RichText, hardBreak)
+
+	var emoji string
+	if callout.Callout.Icon != nil && callout.Callout.Icon.Emoji != nil {
+		emoji = string(*callout.Callout.Icon.Emoji)
+	}
+
+	if alert, ok := githubAlerts[emoji]; ok {
+		text = "[!" + alert + "]\n" + text
+	} else if emoji != "" {
+		// Other callouts are rendered as plain quotes, keeping their emoji
+		text = emoji + " " + text
+	}
+
+	return prefixLines("> ", joinBlocks(text, r.renderBlocks(callout.Callout.Children)))
+}
+
+func renderCode(code nt.Code) string {
+	content := plainText(code.RichText)
+
+	// Fence must be longer than any backtick sequence inside the code
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+
+	language := code.Language
+	if language == "plain text" {
+		language = ""
+	}
+
+	return fence + language + "\n" + content + "\n" + fence
+}
+
+func renderTable(table *nt.TableBlock) string {
+	rows := make([][]string, 0, len(table.Table.Children))
+	for _, child := range table.Table.Children {
+		row, ok := child.(*nt.TableRowBlock)
+		if !ok {
+			continue
+		}
+		cells := make([]string, table.Table.TableWidth)
+		for i, cell := range row.TableRow.Cells {
+			if i < len(cells) {
+				cells[i] = strings.ReplaceAll(renderRichTexts(cell, "<br>"), "|", `\|`)
+			}
+		}
+		rows = append(rows, cells)
+	}
+	if len(rows) == 0 || table.Table.TableWidth == 0 {
+		return ""
+	}
+
+	// GFM tables always have a header row, so the first row becomes one even if the table has no column header
+	lines := make([]string, 0, len(rows)+1)
+	lines = append(lines, "| "+strings.Join(rows[0], " | ")+" |")
+	lines = append(lines, "|"+strings.Repeat(" --- |", table.Table.TableWidth))
+	for _, row := range rows[1:] {
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func isListItem(block nt.Block) bool {
+	switch block.GetType() {
+	case nt.BlockTypeBulletedListItem, nt.BlockTypeNumberedListItem, nt.BlockTypeToDo:
+		return true
+	}
+	return false
+}
+
+// renderImage renders the image with its caption as the alt text
+// URLs of files hosted by Notion expire, so such images get the local path of the same file (matched by its name),
+// or become placeholders that are reported as warnings if there is no such local image
+func (r *renderer) renderImage(image nt.Image) string {
+	caption := plainText(image.Caption)
+	alt := escapeMarkdown(caption, false)
+	if image.External != nil {
+		return fmt.Sprintf("![%s](%s)", alt, image.External.URL)
+	}
+	if image.File == nil {
+		return ""
+	}
+
+	name := hostedFileName(image.File.URL)
+	for i, localImage := range r.localImages {
+		if path.Base(localImage) == name {
+			r.localImages = slices.Delete(r.localImages, i, i+1)
+			return fmt.Sprintf("![%s](%s)", alt, localImage)
+		}
+	}
+
+	slog.Warn("Image is hosted by Notion and its URL expires, rendering a placeholder instead", "file", name, "caption", caption)
+	return fmt.Sprintf("<!-- image hosted by Notion: %s -->", cmp.Or(caption, name))
+}
+
+// hostedFileName returns the name of the file hosted by Notion (the last element of its URL path)
+func hostedFileName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return path.Base(u.Path)
+}
+
+// joinBlocks joins non-empty rendered blocks with blank lines
+func joinBlocks(blocks ...string) string {
+	nonEmpty := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		if b != "" {
+			nonEmpty = append(nonEmpty, b)
+		}
+	}
+	return strings.Join(nonEmpty, "\n\n")
+}
+
+// prefixLines prefixes every line with the given prefix (empty lines get the prefix without trailing spaces)
+func prefixLines(prefix, s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = strings.TrimRight(prefix, " ")
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indentLines indents lines with the given indent (the first line is indented only if asked), empty lines are kept empty
+func indentLines(indent, s string, first bool) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" && (i > 0 || first) {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,214 @@
+package serrano_test
+
+import (
+	"testing"
+
+	"github.com/amberpixels/peppers/internal/serrano"
+	nt "github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func text(content string) nt.RichText {
+	return *nt.NewTextRichText(content)
+}
+
+func annotated(content string, annotations nt.Annotations) nt.RichText {
+	rt := text(content)
+	rt.Annotations = &annotations
+	return rt
+}
+
+func paragraph(richTexts ...nt.RichText) nt.Block {
+	return nt.NewParagraphBlock(nt.Paragraph{RichText: richTexts})
+}
+
+func TestRender(t *testing.T) {
+	emoji := func(e string) *nt.Icon {
+		em := nt.Emoji(e)
+		return &nt.Icon{Type: "emoji", Emoji: &em}
+	}
+
+	tests := []struct {
+		name     string
+		blocks   nt.Blocks
+		expected string
+	}{
+		{
+			name: "Annotations",
+			blocks: nt.Blocks{paragraph(
+				text("Plain "),
+				annotated("bold ", nt.Annotations{Bold: true}),
+				annotated("italic", nt.Annotations{Italic: true}),
+				text(", "),
+				annotated("both", nt.Annotations{Bold: true, Italic: true}),
+				text(", "),
+				annotated("gone", nt.Annotations{Strikethrough: true}),
+				text(", "),
+				annotated("x := `1`", nt.Annotations{Code: true}),
+				text(", "),
+				annotated("under", nt.Annotations{Underline: true}),
+				text(" and "),
+				annotated("marked", nt.Annotations{Color: nt.ColorYellowBackground}),
+			)},
+			expected: "Plain **bold** *italic*, ***both***, ~~gone~~, `` x := `1` ``, <u>under</u> and <mark>marked</mark>\n",
+		},
+		{
+			name: "Links and merged rich texts",
+			blocks: nt.Blocks{paragraph(
+				*nt.NewLinkRichText("docs", "https://example.com"),
+				text(" and "),
+				annotated("long ", nt.Annotations{Bold: true}),
+				annotated("text", nt.Annotations{Bold: true}),
+				text("\nnext line"),
+			)},
+			expected: "[docs](https://example.com) and **long text**\\\nnext line\n",
+		},
+		{
+			name: "Escaped plain text",
+			blocks: nt.Blocks{paragraph(
+				text("# 1. *a* _b_ [c](d) <b>"),
+				annotated("*code*", nt.Annotations{Code: true}),
+				text("\n> quote and 2) item"),
+			)},
+			expected: "\\# 1. \\*a\\* \\_b\\_ \\[c\\](d) \\<b>`*code*`\\\n\\> quote and 2) item\n",
+		},
+		{
+			name: "Headings and divider",
+			blocks: nt.Blocks{
+				nt.NewHeading1Block(nt.Heading{RichText: []nt.RichText{text("Title")}}),
+				nt.NewHeading2Block(nt.Heading{RichText: []nt.RichText{text("Section")}}),
+				nt.NewDividerBlock(),
+				nt.NewHeading3Block(nt.Heading{
+					RichText:     []nt.RichText{text("Toggle")},
+					IsToggleable: true,
+					Children:     nt.Blocks{paragraph(text("Inside"))},
+				}),
+			},
+			expected: "# Title\n\n## Section\n\n---\n\n### Toggle\n\nInside\n",
+		},
+		{
+			name: "Lists",
+			blocks: nt.Blocks{
+				nt.NewBulletedListItemBlock(nt.ListItem{
+					RichText: []nt.RichText{text("One")},
+					Children: nt.Blocks{
+						nt.NewNumberedListItemBlock(nt.ListItem{RichText: []nt.RichText{text("First")}}),
+						nt.NewNumberedListItemBlock(nt.ListItem{RichText: []nt.RichText{text("Second")}}),
+					},
+				}),
+				nt.NewBulletedListItemBlock(nt.ListItem{
+					RichText: []nt.RichText{text("Two")},
+					Children: nt.Blocks{paragraph(text("Details"))},
+				}),
+				nt.NewToDoBlock(nt.ToDo{RichText: []nt.RichText{text("Done")}, Checked: true}),
+				nt.NewToDoBlock(nt.ToDo{RichText: []nt.RichText{text("Todo")}}),
+				paragraph(text("After")),
+			},
+			expected: `- One
+  1. First
+  2. Second
+- Two
+
+  Details
+- [x] Done
+- [ ] Todo
+
+After
+`,
+		},
+		{
+			name: "Quotes and callouts",
+			blocks: nt.Blocks{
+				nt.NewQuoteBlock(nt.Quote{
+					RichText: []nt.RichText{text("Quoted")},
+					Children: nt.Blocks{paragraph(text("More"))},
+				}),
+				nt.NewCalloutBlock(nt.Callout{
+					RichText: []nt.RichText{text("Be careful")},
+					Icon:     emoji("⚠️"),
+				}),
+				nt.NewCalloutBlock(nt.Callout{
+					RichText: []nt.RichText{text("Custom")},
+					Icon:     emoji("🌶️"),
+				}),
+			},
+			expected: "> Quoted\n>\n> More\n\n> [!WARNING]\n> Be careful\n\n> 🌶️ Custom\n",
+		},
+		{
+			name: "Code",
+			blocks: nt.Blocks{
+				nt.NewCodeBlock(nt.Code{Language: "go", RichText: []nt.RichText{text("x := 1")}}),
+				nt.NewCodeBlock(nt.Code{Language: "plain text", RichText: []nt.RichText{text("```\nnested\n```")}}),
+			},
+			expected: "```go\nx := 1\n```\n\n````\n```\nnested\n```\n````\n",
+		},
+		{
+			name: "Toggle, image and equation",
+			blocks: nt.Blocks{
+				nt.NewToggleBlock(nt.Toggle{
+					RichText: []nt.RichText{text("Spoiler <b>")},
+					Children: nt.Blocks{paragraph(text("Hidden"))},
+				}),
+				nt.NewImageBlock(nt.Image{
+					Type:     nt.FileTypeExternal,
+					External: &nt.FileObject{URL: "https://example.com/a.png"},
+					Caption:  []nt.RichText{text("Diagram")},
+				}),
+				nt.NewEquationBlock(nt.Equation{Expression: "e = mc^2"}),
+				nt.NewChildPageBlock("Subpage"),
+			},
+			expected: "<details>\n<summary>Spoiler &lt;b&gt;</summary>\n\nHidden\n\n</details>\n\n" +
+				"![Diagram](https://example.com/a.png)\n\n$$\ne = mc^2\n$$\n",
+		},
+		{
+			name: "Table",
+			blocks: nt.Blocks{nt.NewTableBlock(nt.Table{
+				TableWidth:      2,
+				HasColumnHeader: true,
+				Children: nt.Blocks{
+					nt.NewTableRowBlock(nt.TableRow{Cells: [][]nt.RichText{{text("Name")}, {text("Value")}}}),
+					nt.NewTableRowBlock(nt.TableRow{Cells: [][]nt.RichText{
+						{annotated("a|b", nt.Annotations{Code: true})},
+						{text("multi\nline")},
+					}}),
+				},
+			})},
+			expected: "| Name | Value |\n| --- | --- |\n| `a\\|b` | multi<br>line |\n",
+		},
+		{
+			name:     "Unsupported block",
+			blocks:   nt.Blocks{nt.NewBreadcrumbBlock()},
+			expected: "<!-- unsupported Notion block: breadcrumb -->\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, serrano.Render(tt.blocks))
+		})
+	}
+}
+
+func TestRenderPage(t *testing.T) {
+	assert.Equal(t, "# Readme\n\nHello\n", serrano.RenderPage("Readme", nt.Blocks{paragraph(text("Hello"))}, nil))
+	assert.Equal(t, "# Empty\n", serrano.RenderPage("Empty", nil, nil))
+}
+
+func TestRenderPage_HostedImages(t *testing.T) {
+	hosted := func(url, caption string) nt.Block {
+		return nt.NewImageBlock(nt.Image{
+			Type:    nt.FileTypeFile,
+			File:    &nt.FileObject{URL: url},
+			Caption: []nt.RichText{text(caption)},
+		})
+	}
+	blocks := nt.Blocks{
+		hosted("https://prod-files-secure.s3.us-west-2.amazonaws.com/abc/123/arch.png?X-Amz-Expires=3600", "Architecture"),
+		hosted("https://prod-files-secure.s3.us-west-2.amazonaws.com/abc/456/drawn.png?X-Amz-Expires=3600", "Drawn in Notion"),
+	}
+
+	assert.Equal(t,
+		"# Docs\n\n![Architecture](../img/arch.png)\n\n<!-- image hosted by Notion: Drawn in Notion -->\n",
+		serrano.RenderPage("Docs", blocks, []string{"logo.png", "../img/arch.png"}),
+	)
+}
@@ -1,11 +1,18 @@
 package jalapeno
 
-import nt "github.com/jomei/notionapi"
+import (
+	nt "github.com/jomei/notionapi"
+	mdast "github.com/yuin/goldmark/ast"
+)
 
 // NtBlockBuilder is func that makes a nt.Block from given []bytes source
+// Builders are what block handlers return (see BlockHandler), so they're a part of the handler API
 type NtBlockBuilder struct {
 	build      func(source []byte) nt.Block
 	decorators []func([]byte, nt.Block)
+
+	// node is the Markdown AST node the block is converted from (see SourceNode)
+	node mdast.Node
 }
 type NtBlockBuilders []*NtBlockBuilder
 
@@ -24,6 +31,12 @@ func (b *NtBlockBuilder) Build(source []byte) nt.Block {
 	return block
 }
 
+// SourceNode returns the Markdown AST node the block is converted from (nil if unknown)
+// Blocks of nested documents (e.g. `<details>` content) are bound to nodes of those documents
+func (b *NtBlockBuilder) SourceNode() mdast.Node {
+	return b.node
+}
+
 func (b *NtBlockBuilder) DecorateWith(d func(source []byte, block nt.Block)) {
 	b.decorators = append(b.decorators, d)
 }
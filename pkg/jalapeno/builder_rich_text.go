@@ -1,12 +1,22 @@
 package jalapeno
 
-import nt "github.com/jomei/notionapi"
+import (
+	"cmp"
+	"strings"
+
+	nt "github.com/jomei/notionapi"
+	mdast "github.com/yuin/goldmark/ast"
+)
 
 // NtRichTextBuilder is a builder for nt.RichText
 // It builds a nt.RichText from a given source and optionally can decorate it aftew
+// Builders are what rich text handlers return (see RichTextHandler), so they're a part of the handler API
 type NtRichTextBuilder struct {
 	build      func(source []byte) *nt.RichText
 	decorators []RichTextDecorator
+
+	// node is the Markdown AST node the rich text is converted from (see SourceNode)
+	node mdast.Node
 }
 
 type RichTextDecorator func(*nt.RichText)
@@ -20,22 +30,35 @@ func NewNtRichTextBuilder(build func(source []byte) *nt.RichText) *NtRichTextBui
 	}
 }
 
+// SourceNode returns the Markdown AST node the rich text is converted from (nil if unknown)
+func (b *NtRichTextBuilder) SourceNode() mdast.Node {
+	return b.node
+}
+
 func (b *NtRichTextBuilder) DecorateWith(d RichTextDecorator) {
 	b.decorators = append(b.decorators, d)
 }
 
 func (b *NtRichTextBuilder) Build(source []byte) *nt.RichText {
 	richText := b.build(source)
+	if richText == nil {
+		return nil
+	}
 	for _, d := range b.decorators {
 		d(richText)
 	}
 	return richText
 }
 
+// Build builds all rich texts
+// Rich texts exceeding Notion's content length limit are split into several ones with the same annotations
 func (builders NtRichTextBuilders) Build(source []byte) []nt.RichText {
 	result := make([]nt.RichText, 0)
 	for _, builder := range builders {
-		result = append(result, *builder.Build(source))
+		// Some nodes (e.g. stripped HTML tags) are built as nil rich texts, they are omitted
+		if built := builder.Build(source); built != nil {
+			result = append(result, splitRichText(*built)...)
+		}
 	}
 	return result
 }
@@ -49,10 +72,26 @@ var (
 	italicDecorator        = func(t *nt.RichText) { t.AnnotateItalic() }
 	strikethroughDecorator = func(t *nt.RichText) { t.AnnotateStrikethrough() }
 	codeDecorator          = func(t *nt.RichText) { t.AnnotateCode() }
+	underlineDecorator     = func(t *nt.RichText) { t.AnnotateUnderline() }
+
+	colorDecorator = func(color nt.Color) func(*nt.RichText) {
+		return func(t *nt.RichText) { t.AnnotateColor(color) }
+	}
 
 	linkDecorator = func(urlDestination string) func(*nt.RichText) {
 		return func(t *nt.RichText) { t.MakeLink(urlDestination) }
 	}
+
+	// replaceRunesDecorator replaces characters of the text with the given ones (unknown are kept as is)
+	replaceRunesDecorator = func(replacements map[rune]rune) func(*nt.RichText) {
+		return func(t *nt.RichText) {
+			if t.Text == nil {
+				return
+			}
+			t.Text.Content = strings.Map(func(r rune) rune { return cmp.Or(replacements[r], r) }, t.Text.Content)
+			t.PlainText = t.Text.Content
+		}
+	}
 )
 
 var (
@@ -60,5 +99,8 @@ var (
 	_ RichTextDecorator = italicDecorator
 	_ RichTextDecorator = strikethroughDecorator
 	_ RichTextDecorator = codeDecorator
+	_ RichTextDecorator = underlineDecorator
+	_ RichTextDecorator = colorDecorator(nt.ColorYellowBackground)
+	_ RichTextDecorator = replaceRunesDecorator(superscriptRunes)
 	_ RichTextDecorator = linkDecorator("google.com")
 )
@@ -0,0 +1,82 @@
+package jalapeno
+
+import (
+	"context"
+	"slices"
+
+	nt "github.com/jomei/notionapi"
+	md "github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+)
+
+// Result is a Markdown document converted into a Notion page
+type Result struct {
+	// Blocks is the content of the page
+	Blocks nt.Blocks
+	// Properties holds the page title: the front matter title, otherwise the first H1 heading (taken out of Blocks)
+	// or the default title (see WithDefaultTitle)
+	Properties nt.Properties
+	// Icon and Cover of the page set in the front matter (nil if not set)
+	Icon  *nt.Icon
+	Cover *nt.Image
+	// FrontMatter of the document (nil if there is none). See FrontMatter.Properties for database properties
+	FrontMatter FrontMatter
+	// Diagnostics lists constructs that couldn't be converted without losses
+	Diagnostics Diagnostics
+	// SourceRanges maps blocks (including nested ones) to the lines of the Markdown source they're converted from
+	// Blocks parsed from the raw HTML of `<details>` blocks are mapped to the `<details>` block itself
+	SourceRanges map[nt.Block]SourceRange
+}
+
+// Convert converts the given Markdown document into a Notion page
+// The document is parsed with DefaultMarkdown (use Parser.Convert for other goldmark setups)
+func Convert(ctx context.Context, source []byte, opts ...ParserOption) (*Result, error) {
+	return NewParser(nil, opts...).Convert(ctx, source)
+}
+
+// Convert converts the given Markdown document into a Notion page
+func (p *Parser) Convert(ctx context.Context, source []byte) (*Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := p.parseDocument(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if title := result.FrontMatter.Title(); title != nil {
+		result.Properties = nt.Properties{
+			string(nt.PropertyConfigTypeTitle): nt.TitleProperty{Title: title},
+		}
+	} else {
+		blocks := result.Blocks
+		result.Blocks, result.Properties = preparePageProperties(blocks, p.defaultTitle)
+		// The heading taken as the title is not a block of the page anymore
+		if i := slices.IndexFunc(blocks, func(block nt.Block) bool { return block.GetType() == nt.BlockTypeHeading1 }); i >= 0 {
+			delete(result.SourceRanges, blocks[i])
+		}
+	}
+	result.Icon = result.FrontMatter.Icon()
+	result.Cover = result.FrontMatter.Cover()
+
+	return result, nil
+}
+
+// DefaultMarkdown returns goldmark configured with all Markdown extensions jalapeno supports
+// (GFM, footnotes, definition lists)
+func DefaultMarkdown() md.Markdown {
+	return md.New(
+		md.WithExtensions(
+			extension.GFM,
+			extension.Table,
+			extension.TaskList,
+			extension.Footnote,
+			extension.DefinitionList,
+		),
+		md.WithParserOptions(
+			parser.WithAutoHeadingID(),
+		),
+	)
+}
@@ -0,0 +1,193 @@
+package jalapeno
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	nt "github.com/jomei/notionapi"
+	mdast "github.com/yuin/goldmark/ast"
+	mdtext "github.com/yuin/goldmark/text"
+)
+
+// KindDetails is a NodeKind of the Details node
+var KindDetails = mdast.NewNodeKind("Details")
+
+// Details is a Markdown AST node standing for HTML `<details>` block
+// Goldmark sees it as separate HTML blocks (opening and closing tags) with Markdown content between them,
+// so they are gathered together into the Details node (see groupDetails)
+type Details struct {
+	mdast.BaseBlock
+
+	// Summary is a plain text of the `<summary>` tag
+	Summary string
+
+	// Inner is a raw content placed inside the opening HTML block itself (right after the summary),
+	// it's parsed as a separate Markdown document
+	Inner []byte
+}
+
+// Kind implements Node.Kind.
+func (n *Details) Kind() mdast.NodeKind {
+	return KindDetails
+}
+
+// Dump implements Node.Dump.
+func (n *Details) Dump(source []byte, level int) {
+	mdast.DumpHelper(n, source, level, map[string]string{"Summary": n.Summary}, nil)
+}
+
+var (
+	detailsOpenRe  = regexp.MustCompile(`(?is)^\s*<details(\s[^>]*)?>`)
+	detailsCloseRe = regexp.MustCompile(`(?i)</details\s*>`)
+	// detailsReopenRe matches blocks closing one details and opening the next one (consecutive collapsibles)
+	detailsReopenRe       = regexp.MustCompile(`(?is)^\s*</details\s*>\s*<details(\s[^>]*)?>`)
+	detailsLeadingCloseRe = regexp.MustCompile(`(?i)^\s*</details\s*>`)
+	summaryRe             = regexp.MustCompile(`(?is)^\s*<summary(\s[^>]*)?>(.*?)</summary\s*>`)
+	htmlTagRe             = regexp.MustCompile(`<[^>]*>`)
+)
+
+// groupDetails walks through children of the given node and gathers `<details>` HTML blocks
+// together with all the following siblings (until the matching `</details>`) into Details nodes
+func groupDetails(parent mdast.Node, source []byte) {
+	for child := parent.FirstChild(); child != nil; child = child.NextSibling() {
+		if child.Kind() != mdast.KindHTMLBlock {
+			groupDetails(child, source)
+			continue
+		}
+
+		content := htmlBlockContent(child.(*mdast.HTMLBlock), source) // nolint:errcheck
+		// The block closing the previous details may open the next one
+		if _, ok := child.PreviousSibling().(*Details); ok && detailsReopenRe.Match(content) {
+			content = content[detailsLeadingCloseRe.FindIndex(content)[1]:]
+		}
+		opening := detailsOpenRe.FindIndex(content)
+		if opening == nil {
+			continue
+		}
+
+		// Details is positioned at its opening HTML block
+		details := &Details{}
+		details.SetLines(child.Lines())
+		rest := content[opening[1]:]
+		if summary := summaryRe.FindSubmatchIndex(rest); summary != nil {
+			details.Summary = htmlToPlainText(string(rest[summary[4]:summary[5]]))
+			rest = rest[summary[1]:]
+		}
+
+		// Everything is inside a single HTML block
+		if closing := detailsCloseRe.FindAllIndex(rest, -1); len(closing) > 0 {
+			details.Inner = bytes.TrimSpace(rest[:closing[len(closing)-1][0]])
+			parent.ReplaceChild(parent, child, details)
+			child = details
+			continue
+		}
+		details.Inner = bytes.TrimSpace(rest)
+
+		// Moving following siblings inside until the matching closing tag
+		depth := 0
+	siblings:
+		for sibling := child.NextSibling(); sibling != nil; {
+			next := sibling.NextSibling()
+			if sibling.Kind() == mdast.KindHTMLBlock {
+				siblingContent := htmlBlockContent(sibling.(*mdast.HTMLBlock), source) // nolint:errcheck
+				opens, closes := detailsOpenRe.Match(siblingContent), detailsCloseRe.Match(siblingContent)
+				switch {
+				case detailsReopenRe.Match(siblingContent):
+					// the block closes a details and opens the next one
+					// on the top level it's left in place to be grouped into the next details, nested ones keep the depth
+					if depth == 0 {
+						break siblings
+					}
+				case opens && !closes:
+					depth++
+				case closes && !opens:
+					if depth == 0 {
+						parent.RemoveChild(parent, sibling)
+						break siblings
+					}
+					depth--
+				}
+			}
+
+			parent.RemoveChild(parent, sibling)
+			details.AppendChild(details, sibling)
+			sibling = next
+		}
+
+		parent.ReplaceChild(parent, child, details)
+		groupDetails(details, source)
+		child = details
+	}
+}
+
+// handleDetails converts `<details>` into Notion's toggle block
+func (p *Parser) handleDetails(node mdast.Node) NtBlockBuilders {
+	details := node.(*Details) // nolint:errcheck
+
+	summary := details.Summary
+	if summary == "" {
+		summary = "Details" // same as browsers show
+	}
+
+	children := make(NtBlockBuilders, 0)
+	if len(details.Inner) > 0 {
+		children = append(children, p.parseFragment(details, details.Inner)...)
+	}
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		children = append(children, p.ToBlocks(child)...)
+	}
+
+	return NtBlockBuilders{NewNtBlockBuilder(func(source []byte) nt.Block {
+		return nt.NewToggleBlock(nt.Toggle{
+			RichText: splitRichText(*nt.NewTextRichText(summary)),
+			Children: children.Build(source),
+		})
+	})}
+}
+
+// parseFragment parses the given piece of Markdown (placed inside the origin node) as a separate document
+// Returned builders are bound to the fragment, so they ignore the source given on build
+func (p *Parser) parseFragment(origin mdast.Node, fragment []byte) NtBlockBuilders {
+	// Diagnostics of the fragment point at its origin (the outermost one for nested fragments)
+	if p.origin == nil {
+		fp := *p
+		fp.origin = origin
+		p = &fp
+	}
+
+	tree := p.mdParser.Parser().Parse(mdtext.NewReader(fragment))
+	p.prepareTree(tree, fragment)
+	p.resolveLinks(tree)
+
+	builders := make(NtBlockBuilders, 0)
+	for child := tree.FirstChild(); child != nil; child = child.NextSibling() {
+		for _, builder := range p.ToBlocks(child) {
+			builders = append(builders, NewNtBlockBuilder(func(_ []byte) nt.Block {
+				return builder.Build(fragment)
+			}))
+		}
+	}
+
+	return builders
+}
+
+// htmlBlockContent returns the full raw content of the given HTML block
+func htmlBlockContent(node *mdast.HTMLBlock, source []byte) []byte {
+	var content bytes.Buffer
+	for i := 0; i < node.Lines().Len(); i++ {
+		segment := node.Lines().At(i)
+		content.Write(segment.Value(source))
+	}
+	if node.HasClosure() {
+		content.Write(node.ClosureLine.Value(source))
+	}
+
+	return content.Bytes()
+}
+
+// htmlToPlainText strips all tags from the given HTML snippet
+func htmlToPlainText(s string) string {
+	return strings.TrimSpace(html.UnescapeString(htmlTagRe.ReplaceAllString(s, "")))
+}
@@ -0,0 +1,83 @@
+package jalapeno
+
+import (
+	"fmt"
+	"strings"
+
+	mdast "github.com/yuin/goldmark/ast"
+)
+
+// Diagnostic describes a Markdown construct that couldn't be converted into Notion (or was converted with losses)
+type Diagnostic struct {
+	// Kind is the kind of the Markdown AST node (e.g. "HTMLBlock" or "Link")
+	Kind string
+	// Line and Column point at the node in the Markdown source (1-based, zero if the position is unknown)
+	// Nodes inside `<details>` blocks point at the `<details>` block itself
+	Line   int
+	Column int
+
+	Message string
+}
+
+// String returns the diagnostic in the `line:column: kind: message` form
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s", d.Line, d.Column, d.Kind, d.Message)
+}
+
+// Diagnostics is a list of diagnostics collected while converting a document
+type Diagnostics []Diagnostic
+
+// Err returns DiagnosticsError if there are any diagnostics, nil otherwise
+func (d Diagnostics) Err() error {
+	if len(d) == 0 {
+		return nil
+	}
+	return &DiagnosticsError{Diagnostics: d}
+}
+
+// DiagnosticsError is returned in strict mode (see WithStrictMode) if the document can't be converted without losses
+type DiagnosticsError struct {
+	Diagnostics Diagnostics
+}
+
+func (e *DiagnosticsError) Error() string {
+	messages := make([]string, len(e.Diagnostics))
+	for i, d := range e.Diagnostics {
+		messages[i] = d.String()
+	}
+	return "Markdown can't be converted without losses: " + strings.Join(messages, "; ")
+}
+
+// report adds a diagnostic about the given node into the document being parsed (if any)
+func (p *Parser) report(node mdast.Node, format string, args ...any) {
+	if p.diagnostics == nil {
+		return
+	}
+
+	diagnostic := Diagnostic{Kind: node.Kind().String(), Message: fmt.Sprintf(format, args...)}
+
+	// Nodes of nested documents have no position in the source, so their origin is reported instead
+	positioned := node
+	if p.origin != nil {
+		positioned = p.origin
+	}
+	if offset, ok := nodeStart(positioned); ok {
+		diagnostic.Line, diagnostic.Column = lineColumn(p.source, offset)
+	}
+
+	*p.diagnostics = append(*p.diagnostics, diagnostic)
+}
+
+// nodeStart returns the offset of the given node in the source
+// Nodes having no segments of their own are positioned by their first descendant,
+// or by the closest parent (except the document itself) otherwise
+func nodeStart(node mdast.Node) (int, bool) {
+	for n := node; n != nil && n.Kind() != mdast.KindDocument; n = n.Parent() {
+		for d := n; d != nil; d = d.FirstChild() {
+			if start, _, ok := ownSegments(d); ok {
+				return start, true
+			}
+		}
+	}
+	return 0, false
+}
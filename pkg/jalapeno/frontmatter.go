@@ -0,0 +1,230 @@
+package jalapeno
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	nt "github.com/jomei/notionapi"
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatter is a metadata block (YAML or TOML) placed at the very top of a Markdown document
+type FrontMatter map[string]any
+
+// Front matter keys that have special meaning
+const (
+	frontMatterTitle = "title"
+	frontMatterIcon  = "icon"
+	frontMatterCover = "cover"
+	frontMatterTags  = "tags"
+	// frontMatterPageID is the ID of the Notion page the document is published into
+	frontMatterPageID = "notion_page_id"
+)
+
+// frontMatterDelimiters maps front matter delimiters to their unmarshalers
+var frontMatterDelimiters = map[string]func([]byte, any) error{
+	"---": yaml.Unmarshal,
+	"+++": toml.Unmarshal,
+}
+
+// frontMatterDateLayouts are layouts of string values that are considered to be dates
+var frontMatterDateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+// ExtractFrontMatter detaches the front matter (YAML delimited by `---` or TOML delimited by `+++`)
+// from the given Markdown source.
+// Front matter is blanked in the returned source (line breaks are kept), so positions of the rest
+// of the document stay the same.
+// The returned offset is the end of the front matter block in the source (its closing delimiter included).
+// If there is no front matter, nil FrontMatter, the untouched source and zero offset are returned
+func ExtractFrontMatter(source []byte) (FrontMatter, []byte, int, error) {
+	firstLineEnd := bytes.IndexByte(source, '\n')
+	if firstLineEnd < 0 {
+		return nil, source, 0, nil
+	}
+
+	delimiter := string(bytes.TrimSpace(source[:firstLineEnd]))
+	unmarshal, ok := frontMatterDelimiters[delimiter]
+	if !ok {
+		return nil, source, 0, nil
+	}
+
+	// Looking for the closing delimiter on its own line
+	var contentEnd, blockEnd int
+	for offset := firstLineEnd + 1; offset < len(source); {
+		lineEnd := bytes.IndexByte(source[offset:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(source)
+		} else {
+			lineEnd += offset
+		}
+
+		if string(bytes.TrimSpace(source[offset:lineEnd])) == delimiter {
+			contentEnd, blockEnd = offset, lineEnd
+			break
+		}
+		offset = lineEnd + 1
+	}
+	if blockEnd == 0 {
+		return nil, source, 0, nil
+	}
+
+	frontMatter := make(FrontMatter)
+	if err := unmarshal(source[firstLineEnd+1:contentEnd], &frontMatter); err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to parse front matter: %w", err)
+	}
+
+	blanked := bytes.Clone(source)
+	for i := 0; i < blockEnd; i++ {
+		if blanked[i] != '\n' {
+			blanked[i] = ' '
+		}
+	}
+
+	return frontMatter, blanked, blockEnd, nil
+}
+
+// Title returns the title from the front matter (nil if there is no title)
+func (fm FrontMatter) Title() []nt.RichText {
+	title, ok := fm[frontMatterTitle]
+	if !ok || title == nil {
+		return nil
+	}
+
+	return []nt.RichText{*nt.NewTextRichText(fmt.Sprint(title))}
+}
+
+// PageID returns the ID of the Notion page the document is published into (empty if it's not set)
+// Unlike the title, it stays the same when the document is renamed
+func (fm FrontMatter) PageID() string {
+	pageID, ok := fm[frontMatterPageID]
+	if !ok || pageID == nil {
+		return ""
+	}
+	return fmt.Sprint(pageID)
+}
+
+// Icon returns the page icon from the front matter: it's either an emoji or an URL of an image
+func (fm FrontMatter) Icon() *nt.Icon {
+	icon, ok := fm[frontMatterIcon].(string)
+	if !ok || icon == "" {
+		return nil
+	}
+
+	if isURL(icon) {
+		return &nt.Icon{
+			Type:     nt.FileTypeExternal,
+			External: &nt.FileObject{URL: icon},
+		}
+	}
+
+	return emojiIcon(icon)
+}
+
+// Cover returns the page cover image from the front matter
+func (fm FrontMatter) Cover() *nt.Image {
+	cover, ok := fm[frontMatterCover].(string)
+	if !ok || !isURL(cover) {
+		return nil
+	}
+
+	return &nt.Image{
+		Type:     nt.FileTypeExternal,
+		External: &nt.FileObject{URL: cover},
+	}
+}
+
+// Properties maps the front matter into Notion page properties (for pages living in a database)
+// Property types are guessed from values:
+// booleans become checkboxes, numbers become numbers, dates become dates, URLs become URLs,
+// lists become multi-selects and the rest become rich texts. Nested objects are not supported and skipped.
+func (fm FrontMatter) Properties() nt.Properties {
+	props := make(nt.Properties)
+	if title := fm.Title(); title != nil {
+		props[string(nt.PropertyConfigTypeTitle)] = nt.TitleProperty{Title: title}
+	}
+
+	for key, value := range fm {
+		switch key {
+		case frontMatterTitle, frontMatterIcon, frontMatterCover, frontMatterPageID:
+			continue
+		}
+
+		// Comma separated tags are allowed as a shortcut for a list
+		if tags, ok := value.(string); ok && key == frontMatterTags {
+			value = splitTags(tags)
+		}
+
+		if prop := frontMatterProperty(value); prop != nil {
+			props[key] = prop
+		}
+	}
+
+	return props
+}
+
+// frontMatterProperty converts a single front matter value into a Notion property
+func frontMatterProperty(value any) nt.Property {
+	switch v := value.(type) {
+	case bool:
+		return nt.CheckboxProperty{Checkbox: v}
+	case int:
+		return nt.NumberProperty{Number: float64(v)}
+	case int64:
+		return nt.NumberProperty{Number: float64(v)}
+	case float64:
+		return nt.NumberProperty{Number: v}
+	case time.Time:
+		return dateProperty(v)
+	case string:
+		if isURL(v) {
+			return nt.URLProperty{URL: v}
+		}
+		for _, layout := range frontMatterDateLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return dateProperty(t)
+			}
+		}
+		return nt.RichTextProperty{RichText: []nt.RichText{*nt.NewTextRichText(v)}}
+	case []any:
+		options := make([]nt.Option, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case []any, map[string]any:
+				continue
+			}
+			options = append(options, nt.Option{Name: strings.ReplaceAll(fmt.Sprint(item), ",", " ")})
+		}
+		return nt.MultiSelectProperty{MultiSelect: options}
+	default:
+		return nil
+	}
+}
+
+func splitTags(s string) []any {
+	tags := make([]any, 0)
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func dateProperty(t time.Time) nt.DateProperty {
+	// TOML local dates (and datetimes) come in fake "*-local" locations, we treat them as UTC
+	if strings.HasSuffix(t.Location().String(), "-local") {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	}
+
+	date := nt.Date(t)
+	return nt.DateProperty{Date: &nt.DateObject{Start: &date}}
+}
+
+func isURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
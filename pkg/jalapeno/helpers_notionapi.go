@@ -0,0 +1,121 @@
+package jalapeno
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	nt "github.com/jomei/notionapi"
+)
+
+// MaxRichTextContentLength is the maximum length of a single rich text content allowed by Notion API
+// Note: Notion measures it in UTF-16 code units
+const MaxRichTextContentLength = 2000
+
+// splitRichText splits the given rich text into several ones if its content is too long for Notion
+// Each part keeps annotations and link of the original rich text
+func splitRichText(rt nt.RichText) []nt.RichText {
+	if rt.Text == nil || len(rt.Text.Content) <= MaxRichTextContentLength {
+		// byte length is always >= UTF-16 length, so it's a cheap check for most of cases
+		return []nt.RichText{rt}
+	}
+
+	chunks := make([]string, 0)
+	var chunk strings.Builder
+	var chunkLength int
+	for _, r := range rt.Text.Content {
+		runeLength := utf16.RuneLen(r)
+		if runeLength < 0 { // invalid rune is replaced with U+FFFD
+			runeLength = 1
+		}
+		if chunkLength+runeLength > MaxRichTextContentLength {
+			chunks = append(chunks, chunk.String())
+			chunk.Reset()
+			chunkLength = 0
+		}
+		chunk.WriteRune(r)
+		chunkLength += runeLength
+	}
+	if chunk.Len() > 0 {
+		chunks = append(chunks, chunk.String())
+	}
+	if len(chunks) == 1 {
+		return []nt.RichText{rt}
+	}
+
+	result := make([]nt.RichText, len(chunks))
+	for i, content := range chunks {
+		part := rt
+
+		text := *rt.Text
+		text.Content = content
+		part.Text = &text
+		part.PlainText = content
+
+		if rt.Annotations != nil {
+			annotations := *rt.Annotations
+			part.Annotations = &annotations
+		}
+
+		result[i] = part
+	}
+
+	return result
+}
+
+// splitRichTexts splits all given rich texts that are too long for Notion (see splitRichText)
+func splitRichTexts(rts []nt.RichText) []nt.RichText {
+	result := make([]nt.RichText, 0, len(rts))
+	for _, rt := range rts {
+		result = append(result, splitRichText(rt)...)
+	}
+	return result
+}
+
+func nonEmptyRichTexts(rts []nt.RichText) []nt.RichText {
+	for i, rt := range rts {
+		if rt.PlainText == "" {
+			rts = append(rts[:i], rts[i+1:]...)
+		}
+	}
+	return rts
+}
+
+// html2notion is a hacky function that converts HTML to Notion-compatible text
+// It's very simple, and in future is considered to be more complex
+// Deprecated: don't tend to use it very often, it's subject to change
+//
+// Note: inline tags (<b>, <i>, <a>, etc) are handled as InlineHTML nodes (see groupInlineHTML)
+//
+//	TODO(amberpixels): add support HTML blocks
+//	  Note: we want to support basic HTML that is usually used in Markdown:
+//	  <p> (for centering), <img> (for images)
+func html2notion(contentHTML string) string {
+	// sanitizing first
+	contentHTML = strings.TrimSpace(contentHTML)
+
+	// Handling edge cases:
+	if isHTMLBreak(contentHTML) {
+		return "\n"
+	}
+
+	return contentHTML // simply return raw html back (letter case preserved)
+}
+
+// emojiIcon returns a Notion icon made of the given emoji
+func emojiIcon(emoji string) *nt.Icon {
+	e := nt.Emoji(emoji)
+	return &nt.Icon{Type: "emoji", Emoji: &e}
+}
+
+var superscriptDigits = []rune("⁰¹²³⁴⁵⁶⁷⁸⁹")
+
+// footnoteMarker returns a superscript-like marker of the footnote with the given index
+// Notion doesn't support superscript, so unicode superscript digits are used instead
+func footnoteMarker(index int) string {
+	var marker strings.Builder
+	for _, digit := range strconv.Itoa(index) {
+		marker.WriteRune(superscriptDigits[digit-'0'])
+	}
+	return marker.String()
+}
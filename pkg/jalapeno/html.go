@@ -0,0 +1,626 @@
+package jalapeno
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+
+	nt "github.com/jomei/notionapi"
+	mdast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// KindInlineHTML is a NodeKind of the InlineHTML node
+var KindInlineHTML = mdast.NewNodeKind("InlineHTML")
+
+// InlineHTML is a Markdown AST node standing for an inline HTML element (e.g. `<b>text</b>`)
+// Goldmark sees opening and closing tags as separate RawHTML nodes with the content between them as siblings,
+// so they are gathered together into the InlineHTML node (see groupInlineHTML)
+type InlineHTML struct {
+	mdast.BaseInline
+
+	// Tag is a lowercased name of the HTML tag
+	Tag string
+
+	// Href is a link destination of `<a>` tag
+	Href string
+}
+
+// Kind implements Node.Kind.
+func (n *InlineHTML) Kind() mdast.NodeKind {
+	return KindInlineHTML
+}
+
+// Dump implements Node.Dump.
+func (n *InlineHTML) Dump(source []byte, level int) {
+	mdast.DumpHelper(n, source, level, map[string]string{"Tag": n.Tag, "Href": n.Href}, nil)
+}
+
+var (
+	htmlTagNameRe = regexp.MustCompile(`^<(/?)([A-Za-z][A-Za-z0-9-]*)(\s[^>]*)?>$`)
+	htmlHrefRe    = regexp.MustCompile(`(?i)\shref\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	htmlBreakRe   = regexp.MustCompile(`(?i)^<br\s*/?>$`)
+)
+
+// htmlTag is a parsed single HTML tag
+type htmlTag struct {
+	name    string
+	closing bool
+	href    string
+}
+
+// parseHTMLTag parses the given raw HTML tag. It returns nil if it's not an opening or closing tag
+// (e.g. a comment or a self-closing tag)
+func parseHTMLTag(raw string) *htmlTag {
+	raw = strings.TrimSpace(raw)
+	if strings.HasSuffix(raw, "/>") {
+		return nil
+	}
+
+	matches := htmlTagNameRe.FindStringSubmatch(raw)
+	if matches == nil {
+		return nil
+	}
+
+	tag := &htmlTag{
+		name:    strings.ToLower(matches[2]),
+		closing: matches[1] == "/",
+	}
+	if href := htmlHrefRe.FindStringSubmatch(matches[3]); href != nil {
+		tag.href = href[1] + href[2] + href[3]
+	}
+
+	return tag
+}
+
+// groupInlineHTML walks through the given node and gathers paired inline HTML tags
+// together with all nodes between them into InlineHTML nodes
+// Unpaired tags are kept as RawHTML nodes (they are stripped while converting into rich texts)
+func groupInlineHTML(parent mdast.Node, source []byte) {
+	for child := parent.FirstChild(); child != nil; child = child.NextSibling() {
+		raw, ok := child.(*mdast.RawHTML)
+		if !ok {
+			groupInlineHTML(child, source)
+			continue
+		}
+
+		opening := parseHTMLTag(string(contentFromSegments(raw.Segments, source)))
+		if opening == nil || opening.closing {
+			continue
+		}
+
+		closing := findClosingTag(child, opening.name, source)
+		if closing == nil {
+			continue
+		}
+
+		element := &InlineHTML{Tag: opening.name, Href: opening.href}
+		for sibling := child.NextSibling(); sibling != closing; {
+			next := sibling.NextSibling()
+			parent.RemoveChild(parent, sibling)
+			element.AppendChild(element, sibling)
+			sibling = next
+		}
+		parent.RemoveChild(parent, closing)
+		parent.ReplaceChild(parent, child, element)
+
+		groupInlineHTML(element, source)
+		child = element
+	}
+}
+
+// parseInlineImages walks through the given node and replaces inline `<img>` tags with Image nodes
+// (converted the same way as Markdown images), `<a>` elements holding just an image become links of the image
+// It must run after groupInlineHTML
+func parseInlineImages(parent mdast.Node, source []byte) {
+	for child := parent.FirstChild(); child != nil; child = child.NextSibling() {
+		switch v := child.(type) {
+		case *mdast.RawHTML:
+			image := parseHTMLImage(string(contentFromSegments(v.Segments, source)))
+			if image == nil {
+				continue
+			}
+			parent.ReplaceChild(parent, child, image)
+			child = image
+		case *InlineHTML:
+			parseInlineImages(child, source)
+			if v.Tag != "a" || v.Href == "" || v.ChildCount() != 1 || v.FirstChild().Kind() != mdast.KindImage {
+				continue
+			}
+			link := mdast.NewLink()
+			link.Destination = []byte(v.Href)
+			link.AppendChild(link, v.FirstChild())
+			parent.ReplaceChild(parent, child, link)
+			child = link
+		default:
+			parseInlineImages(child, source)
+		}
+	}
+}
+
+// parseHTMLImage converts the given raw `<img>` tag into an Image node (alt text becomes its content)
+// It returns nil if it's not an image
+func parseHTMLImage(raw string) *mdast.Image {
+	nodes, err := html.ParseFragment(strings.NewReader(raw), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil || len(nodes) != 1 || nodes[0].DataAtom != atom.Img || htmlAttr(nodes[0], "src") == "" {
+		return nil
+	}
+
+	image := mdast.NewImage(mdast.NewLink())
+	image.Destination = []byte(htmlAttr(nodes[0], "src"))
+	if alt := htmlAttr(nodes[0], "alt"); alt != "" {
+		image.AppendChild(image, mdast.NewString([]byte(alt)))
+	}
+
+	return image
+}
+
+// findClosingTag looks for the sibling that closes the HTML tag opened by the given node
+func findClosingTag(opening mdast.Node, name string, source []byte) mdast.Node {
+	depth := 0
+	for sibling := opening.NextSibling(); sibling != nil; sibling = sibling.NextSibling() {
+		raw, ok := sibling.(*mdast.RawHTML)
+		if !ok {
+			continue
+		}
+
+		tag := parseHTMLTag(string(contentFromSegments(raw.Segments, source)))
+		if tag == nil || tag.name != name {
+			continue
+		}
+
+		switch {
+		case !tag.closing:
+			depth++
+		case depth > 0:
+			depth--
+		default:
+			return sibling
+		}
+	}
+
+	return nil
+}
+
+// inlineHTMLDecorator returns the decorator for rich texts inside the given inline HTML element
+// Unknown tags have no decorator: they are stripped, keeping their content
+func inlineHTMLDecorator(element *InlineHTML) RichTextDecorator {
+	switch element.Tag {
+	case "b", "strong":
+		return boldDecorator
+	case "i", "em":
+		return italicDecorator
+	case "s", "del", "strike":
+		return strikethroughDecorator
+	case "code", "kbd":
+		return codeDecorator
+	case "u", "ins":
+		return underlineDecorator
+	case "mark":
+		return colorDecorator(nt.ColorYellowBackground)
+	case "sup":
+		return replaceRunesDecorator(superscriptRunes)
+	case "sub":
+		return replaceRunesDecorator(subscriptRunes)
+	case "a":
+		if element.Href != "" {
+			return linkDecorator(element.Href)
+		}
+	}
+
+	return nil
+}
+
+// Notion doesn't support superscript and subscript, so unicode characters are used where possible
+var (
+	superscriptRunes = map[rune]rune{
+		'0': '⁰', '1': '¹', '2': '²', '3': '³', '4': '⁴', '5': '⁵', '6': '⁶', '7': '⁷', '8': '⁸', '9': '⁹',
+		'+': '⁺', '-': '⁻', '=': '⁼', '(': '⁽', ')': '⁾', 'i': 'ⁱ', 'n': 'ⁿ',
+	}
+	subscriptRunes = map[rune]rune{
+		'0': '₀', '1': '₁', '2': '₂', '3': '₃', '4': '₄', '5': '₅', '6': '₆', '7': '₇', '8': '₈', '9': '₉',
+		'+': '₊', '-': '₋', '=': '₌', '(': '₍', ')': '₎',
+	}
+)
+
+// isHTMLBreak returns true if the given raw HTML is a line break tag
+func isHTMLBreak(raw string) bool {
+	return htmlBreakRe.MatchString(strings.TrimSpace(raw))
+}
+
+// KindHTMLFragment is a NodeKind of the HTMLFragment node
+var KindHTMLFragment = mdast.NewNodeKind("HTMLFragment")
+
+// HTMLFragment is a Markdown AST node standing for an HTML block that is parsed into HTML nodes
+// (so it can be converted into Notion blocks instead of a raw HTML text)
+type HTMLFragment struct {
+	mdast.BaseBlock
+
+	Nodes []*html.Node
+}
+
+// Kind implements Node.Kind.
+func (n *HTMLFragment) Kind() mdast.NodeKind {
+	return KindHTMLFragment
+}
+
+// Dump implements Node.Dump.
+func (n *HTMLFragment) Dump(source []byte, level int) {
+	mdast.DumpHelper(n, source, level, nil, nil)
+}
+
+// htmlBlockTags are top-level HTML tags that HTML blocks are converted from
+// HTML blocks with other tags are kept as raw HTML
+var htmlBlockTags = map[atom.Atom]struct{}{
+	atom.P:       {},
+	atom.Div:     {},
+	atom.Center:  {},
+	atom.Img:     {},
+	atom.A:       {},
+	atom.Picture: {},
+	atom.Table:   {},
+}
+
+// parseHTMLBlocks walks through the given node and replaces HTML blocks made of supported tags
+// with HTMLFragment nodes
+// Tables split into several blocks are gathered together first (see gatherHTMLTable)
+func parseHTMLBlocks(parent mdast.Node, source []byte, r renderer.Renderer) {
+	for child := parent.FirstChild(); child != nil; child = child.NextSibling() {
+		block, ok := child.(*mdast.HTMLBlock)
+		if !ok {
+			parseHTMLBlocks(child, source, r)
+			continue
+		}
+
+		content := sanitizeMarkdownLintComments(string(htmlBlockContent(block, source)))
+		table, tableSiblings := gatherHTMLTable(block, source, r)
+		if tableSiblings != nil {
+			content = sanitizeMarkdownLintComments(table)
+		}
+		nodes, err := html.ParseFragment(strings.NewReader(content), &html.Node{
+			Type:     html.ElementNode,
+			Data:     "body",
+			DataAtom: atom.Body,
+		})
+		if err != nil || !isSupportedHTMLBlock(nodes) {
+			continue
+		}
+
+		fragment := &HTMLFragment{Nodes: nodes}
+		fragment.SetLines(block.Lines())
+		// Gathered table spans from its opening to its closing HTML block
+		for _, sibling := range tableSiblings {
+			if sibling.Kind() == mdast.KindHTMLBlock {
+				fragment.Lines().AppendAll(sibling.Lines().Sliced(0, sibling.Lines().Len()))
+			}
+			parent.RemoveChild(parent, sibling)
+		}
+		parent.ReplaceChild(parent, child, fragment)
+		child = fragment
+	}
+}
+
+var (
+	htmlTableOpenRe  = regexp.MustCompile(`(?i)<table[\s>]`)
+	htmlTableCloseRe = regexp.MustCompile(`(?i)</table\s*>`)
+)
+
+// gatherHTMLTable gathers the HTML of the `<table>` opened (but not closed) by the given HTML block
+// with all the following siblings until the matching `</table>`
+// Goldmark splits HTML blocks on blank lines (e.g. around Markdown lists inside cells),
+// so Markdown siblings are rendered into HTML to be flattened into cells as well
+// It returns the whole HTML of the table and the gathered siblings, or nil siblings if there is no table to gather
+func gatherHTMLTable(block *mdast.HTMLBlock, source []byte, r renderer.Renderer) (string, []mdast.Node) {
+	tableDepth := func(content []byte) int {
+		return len(htmlTableOpenRe.FindAllIndex(content, -1)) - len(htmlTableCloseRe.FindAllIndex(content, -1))
+	}
+
+	var table bytes.Buffer
+	table.Write(htmlBlockContent(block, source))
+	depth := tableDepth(table.Bytes())
+	if depth <= 0 {
+		return "", nil
+	}
+
+	siblings := make([]mdast.Node, 0)
+	for sibling := block.NextSibling(); sibling != nil && depth > 0; sibling = sibling.NextSibling() {
+		siblings = append(siblings, sibling)
+		if htmlBlock, ok := sibling.(*mdast.HTMLBlock); ok {
+			content := htmlBlockContent(htmlBlock, source)
+			depth += tableDepth(content)
+			table.Write(content)
+			continue
+		}
+		if err := r.Render(&table, source, sibling); err != nil {
+			return "", nil
+		}
+	}
+	if depth > 0 {
+		return "", nil
+	}
+
+	return table.String(), siblings
+}
+
+// isSupportedHTMLBlock returns true if all top-level elements of the given HTML are supported block tags
+func isSupportedHTMLBlock(nodes []*html.Node) bool {
+	var elements int
+	for _, n := range nodes {
+		if n.Type != html.ElementNode {
+			continue
+		}
+		if _, ok := htmlBlockTags[n.DataAtom]; !ok {
+			return false
+		}
+		elements++
+	}
+
+	return elements > 0
+}
+
+// handleHTMLFragment converts parsed HTML block into Notion blocks
+func (p *Parser) handleHTMLFragment(node mdast.Node) NtBlockBuilders {
+	converter := &htmlConverter{
+		blocks: make(NtBlockBuilders, 0),
+		report: func(format string, args ...any) { p.report(node, format, args...) },
+	}
+	for _, n := range node.(*HTMLFragment).Nodes { // nolint:errcheck
+		converter.walk(n, nil)
+	}
+	converter.flush()
+
+	return converter.blocks
+}
+
+// htmlConverter converts HTML nodes into Notion blocks
+// Inline content is gathered into paragraphs, while block elements (paragraphs, images, etc) break them
+// In cell mode (for table cells) everything is gathered into rich texts: block elements just break lines
+type htmlConverter struct {
+	blocks NtBlockBuilders
+	inline []nt.RichText
+	cell   bool
+
+	// report reports a diagnostic for the converted HTML block (it's nil in cell mode)
+	report func(format string, args ...any)
+}
+
+func (c *htmlConverter) walk(n *html.Node, decorators []RichTextDecorator) {
+	switch n.Type {
+	case html.TextNode:
+		c.addText(n.Data, decorators)
+		return
+	case html.ElementNode:
+		// handled below
+	default:
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		c.inline = append(c.inline, *nt.NewTextRichText("\n"))
+	case atom.Img:
+		if c.cell {
+			c.addText(htmlAttr(n, "alt"), decorators)
+			return
+		}
+		// Notion rejects images without URLs
+		if htmlAttr(n, "src") == "" {
+			c.report("image has no src, its alt text is kept as plain text")
+			c.addText(htmlAttr(n, "alt"), decorators)
+			return
+		}
+		c.flush()
+		c.blocks = append(c.blocks, htmlImage(n, decorators))
+	case atom.Table:
+		if c.cell {
+			c.walkChildren(n, decorators)
+			return
+		}
+		c.flush()
+		c.blocks = append(c.blocks, htmlTable(n))
+	case atom.Li:
+		c.breakLine()
+		c.addText(htmlListMarker(n), nil)
+		c.walkChildren(n, decorators)
+		c.breakLine()
+	case atom.P, atom.Div, atom.Center, atom.Picture, atom.Figure, atom.Section, atom.Ul, atom.Ol, atom.Tr:
+		c.breakLine()
+		c.walkChildren(n, decorators)
+		c.breakLine()
+	case atom.Script, atom.Style:
+		// never shown
+	default:
+		if d := inlineHTMLDecorator(&InlineHTML{Tag: n.Data, Href: htmlAttr(n, "href")}); d != nil {
+			decorators = append(decorators[:len(decorators):len(decorators)], d)
+		}
+		c.walkChildren(n, decorators)
+	}
+}
+
+func (c *htmlConverter) walkChildren(n *html.Node, decorators []RichTextDecorator) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		c.walk(child, decorators)
+	}
+}
+
+// addText adds the given text (with HTML whitespace collapsing) to the current paragraph
+func (c *htmlConverter) addText(text string, decorators []RichTextDecorator) {
+	text = htmlWhitespaceRe.ReplaceAllString(text, " ")
+	if len(c.inline) == 0 || strings.HasSuffix(c.inline[len(c.inline)-1].PlainText, " ") ||
+		strings.HasSuffix(c.inline[len(c.inline)-1].PlainText, "\n") {
+		text = strings.TrimLeft(text, " ")
+	}
+	if text == "" {
+		return
+	}
+
+	rt := nt.NewTextRichText(text)
+	for _, d := range decorators {
+		d(rt)
+	}
+	c.inline = append(c.inline, *rt)
+}
+
+// breakLine finishes the current paragraph (or the current line in cell mode)
+func (c *htmlConverter) breakLine() {
+	if !c.cell {
+		c.flush()
+		return
+	}
+
+	c.inline = trimTrailingSpaces(c.inline)
+	if len(c.inline) > 0 && !strings.HasSuffix(c.inline[len(c.inline)-1].PlainText, "\n") {
+		c.inline = append(c.inline, *nt.NewTextRichText("\n"))
+	}
+}
+
+// flush finishes the current paragraph (if there is any text)
+func (c *htmlConverter) flush() {
+	richTexts := trimTrailingSpaces(c.inline)
+	c.inline = nil
+	if len(richTexts) == 0 {
+		return
+	}
+
+	c.blocks = append(c.blocks, NewNtBlockBuilder(func(_ []byte) nt.Block {
+		return nt.NewParagraphBlock(nt.Paragraph{RichText: splitRichTexts(richTexts)})
+	}))
+}
+
+// trimTrailingSpaces removes trailing whitespace (it's not shown in HTML)
+func trimTrailingSpaces(richTexts []nt.RichText) []nt.RichText {
+	for len(richTexts) > 0 {
+		last := &richTexts[len(richTexts)-1]
+		trimmed := strings.TrimRight(last.PlainText, " \n")
+		if trimmed != "" {
+			last.PlainText, last.Text.Content = trimmed, trimmed
+			break
+		}
+		richTexts = richTexts[:len(richTexts)-1]
+	}
+
+	return richTexts
+}
+
+// htmlListMarker returns the marker of the given list item: a bullet or a number (for ordered lists)
+func htmlListMarker(li *html.Node) string {
+	if li.Parent == nil || li.Parent.DataAtom != atom.Ol {
+		return "• "
+	}
+
+	number := 1
+	for sibling := li.PrevSibling; sibling != nil; sibling = sibling.PrevSibling {
+		if sibling.DataAtom == atom.Li {
+			number++
+		}
+	}
+	return strconv.Itoa(number) + ". "
+}
+
+// htmlTable converts `<table>` into Notion's table block (the same way as Markdown tables are converted)
+// The first row is a column header if it's inside `<thead>` or made of `<th>` cells only.
+// The first column is a row header if all other rows start with `<th>` cell.
+// Cells content is flattened into rich texts (block elements like lists and paragraphs become lines)
+func htmlTable(table *html.Node) *NtBlockBuilder {
+	type htmlRow struct {
+		cells       [][]nt.RichText
+		inHead      bool
+		headerCells int
+		firstHeader bool
+	}
+
+	rows := make([]htmlRow, 0)
+	var collectRows func(n *html.Node, inHead bool)
+	collectRows = func(n *html.Node, inHead bool) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			switch child.DataAtom {
+			case atom.Thead:
+				collectRows(child, true)
+			case atom.Tbody, atom.Tfoot:
+				collectRows(child, inHead)
+			case atom.Tr:
+				row := htmlRow{inHead: inHead}
+				for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.DataAtom != atom.Th && cell.DataAtom != atom.Td {
+						continue
+					}
+					if cell.DataAtom == atom.Th {
+						row.headerCells++
+						row.firstHeader = row.firstHeader || len(row.cells) == 0
+					}
+
+					converter := &htmlConverter{cell: true}
+					converter.walkChildren(cell, nil)
+					// splitRichTexts never returns nil: Notion rejects null cells
+					row.cells = append(row.cells, splitRichTexts(trimTrailingSpaces(converter.inline)))
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	collectRows(table, false)
+
+	var width int
+	cells := make([][][]nt.RichText, 0, len(rows))
+	for _, row := range rows {
+		width = max(width, len(row.cells))
+		cells = append(cells, row.cells)
+	}
+
+	hasColumnHeader := len(rows) > 0 && (rows[0].inHead || rows[0].headerCells > 0 && rows[0].headerCells == len(rows[0].cells))
+	bodyRows := rows
+	if hasColumnHeader {
+		bodyRows = rows[1:]
+	}
+	hasRowHeader := len(bodyRows) > 0
+	for _, row := range bodyRows {
+		hasRowHeader = hasRowHeader && row.firstHeader
+	}
+
+	return NewNtBlockBuilder(func(_ []byte) nt.Block {
+		if width == 0 {
+			return nil
+		}
+		return newTableBlock(width, hasColumnHeader, hasRowHeader, cells)
+	})
+}
+
+var htmlWhitespaceRe = regexp.MustCompile(`[ \t\r\n\f]+`)
+
+// htmlImage converts `<img>` into an image block: alt text becomes the caption (linked if image is inside `<a>`)
+func htmlImage(n *html.Node, decorators []RichTextDecorator) *NtBlockBuilder {
+	url := htmlAttr(n, "src")
+	caption := make([]nt.RichText, 0)
+	if alt := htmlAttr(n, "alt"); alt != "" {
+		rt := nt.NewTextRichText(alt)
+		for _, d := range decorators {
+			d(rt)
+		}
+		caption = append(caption, *rt)
+	}
+
+	return NewNtBlockBuilder(func(_ []byte) nt.Block {
+		return nt.NewImageBlock(nt.Image{
+			Type:     nt.FileTypeExternal,
+			External: &nt.FileObject{URL: url},
+			Caption:  splitRichTexts(caption),
+		})
+	})
+}
+
+// htmlAttr returns the value of the given attribute of the HTML node
+func htmlAttr(n *html.Node, name string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,1146 @@
+// Package jalapeno is a library that provides Markdown -> Notion conversion
+// Convert is the entry point: it turns a Markdown document into Notion blocks and page properties
+package jalapeno
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	nt "github.com/jomei/notionapi"
+	md "github.com/yuin/goldmark"
+	mdast "github.com/yuin/goldmark/ast"
+	mdastx "github.com/yuin/goldmark/extension/ast"
+	mdtext "github.com/yuin/goldmark/text"
+	mdutil "github.com/yuin/goldmark/util"
+)
+
+// Parser stands for an instance
+type Parser struct {
+	mdParser md.Markdown
+
+	footnoteStyle       FootnoteStyle
+	definitionListStyle DefinitionListStyle
+	headingStrategy     HeadingStrategy
+	toggleHeadingLevel  int
+	linkResolver        LinkResolver
+	defaultTitle        string
+	defaultCodeLanguage string
+	htmlStrategy        HTMLStrategy
+	blockHandlers       map[mdast.NodeKind]BlockHandler
+	richTextHandlers    map[mdast.NodeKind]RichTextHandler
+
+	strict bool
+
+	// State of the document being parsed (see parseDocument)
+	source       []byte
+	diagnostics  *Diagnostics
+	sourceRanges map[nt.Block]SourceRange
+	origin       mdast.Node // set for nested fragments (see parseFragment)
+	// shiftHeadings is set if HeadingStrategyShift applies to the document (see titleHeadingOnly)
+	shiftHeadings bool
+}
+
+// NewParser creates a parser converting Markdown parsed by the given goldmark instance
+// If it's nil, DefaultMarkdown is used
+func NewParser(mdParser md.Markdown, opts ...ParserOption) *Parser {
+	if mdParser == nil {
+		mdParser = DefaultMarkdown()
+	}
+
+	p := &Parser{
+		mdParser:            mdParser,
+		footnoteStyle:       FootnoteStyleSection,
+		definitionListStyle: DefinitionListStyleParagraph,
+		headingStrategy:     HeadingStrategyClamp,
+		defaultTitle:        DefaultTitle,
+		defaultCodeLanguage: "plain text",
+		htmlStrategy:        HTMLStrategyText,
+		blockHandlers:       make(map[mdast.NodeKind]BlockHandler),
+		richTextHandlers:    make(map[mdast.NodeKind]RichTextHandler),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// ParseBlocks parses the given markdown source into Notion Blocks
+// and diagnostics of constructs that couldn't be converted without losses
+// Front matter (if any) is skipped
+func (p *Parser) ParseBlocks(source []byte) (nt.Blocks, Diagnostics, error) {
+	doc, err := p.parseDocument(source)
+	if doc == nil {
+		return nil, nil, err
+	}
+	return doc.Blocks, doc.Diagnostics, err
+}
+
+// parseDocument parses the given Markdown document collecting diagnostics and source ranges of its conversion
+// Only Blocks, FrontMatter, Diagnostics and SourceRanges of the result are filled
+// Parser itself is kept untouched, so it can be shared between goroutines
+// In strict mode the error is returned (with the result holding only diagnostics) if there are any diagnostics
+func (p *Parser) parseDocument(source []byte) (*Result, error) {
+	frontMatter, source, _, err := ExtractFrontMatter(source)
+	if err != nil {
+		return nil, err
+	}
+
+	// Front matter is blanked out in the source, so positions of nodes are kept
+	diagnostics := make(Diagnostics, 0)
+	dp := *p
+	dp.source = source
+	dp.diagnostics = &diagnostics
+	dp.sourceRanges = make(map[nt.Block]SourceRange)
+
+	tree := dp.mdParser.Parser().Parse(mdtext.NewReader(source))
+	dp.prepareTree(tree, source)
+	dp.resolveLinks(tree)
+	dp.shiftHeadings = p.headingStrategy == HeadingStrategyShift && frontMatter.Title() == nil && titleHeadingOnly(tree)
+
+	nodes := make([]mdast.Node, 0)
+	err = mdast.Walk(tree, func(node mdast.Node, entering bool) (mdast.WalkStatus, error) {
+		if !entering || node.Kind() == mdast.KindDocument {
+			return mdast.WalkContinue, nil
+		}
+
+		nodes = append(nodes, node)
+
+		return mdast.WalkSkipChildren, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk parsed Markdown AST: %w", err)
+	}
+
+	blocks := dp.handleSections(nodes).Build(source)
+	if p.strict && len(diagnostics) > 0 {
+		return &Result{Diagnostics: diagnostics}, diagnostics.Err()
+	}
+
+	return &Result{
+		Blocks:       blocks,
+		FrontMatter:  frontMatter,
+		Diagnostics:  diagnostics,
+		SourceRanges: dp.sourceRanges,
+	}, nil
+}
+
+// titleHeadingOnly returns true if the only H1 of the given document is its top-level block, i.e. it becomes the page title
+// (see preparePageProperties). Other headings can be shifted up then without colliding with H1 ones
+func titleHeadingOnly(tree mdast.Node) bool {
+	var h1, topLevel int
+	_ = mdast.Walk(tree, func(node mdast.Node, entering bool) (mdast.WalkStatus, error) { //nolint:errcheck
+		if heading, ok := node.(*mdast.Heading); ok && entering && heading.Level == 1 {
+			h1++
+			if node.Parent() == tree {
+				topLevel++
+			}
+		}
+		return mdast.WalkContinue, nil
+	})
+	return h1 == 1 && topLevel == 1
+}
+
+// prepareTree gathers together HTML constructs that goldmark splits into separate nodes
+// (e.g. `<details>` blocks with Markdown inside, paired inline tags) and parses supported HTML blocks and inline images
+func (p *Parser) prepareTree(tree mdast.Node, source []byte) {
+	groupDetails(tree, source)
+	parseHTMLBlocks(tree, source, p.mdParser.Renderer())
+	groupInlineHTML(tree, source)
+	parseInlineImages(tree, source)
+}
+
+// handleSections converts given top-level nodes into blocks
+// If toggle headings are enabled, headings of the chosen level become toggleable headings
+// with all the content of their sections (until the next heading of the same or higher level) nested inside
+func (p *Parser) handleSections(nodes []mdast.Node) NtBlockBuilders {
+	blockBuilders := make(NtBlockBuilders, 0)
+	for i := 0; i < len(nodes); i++ {
+		heading, ok := nodes[i].(*mdast.Heading)
+		if !ok || p.toggleHeadingLevel == 0 || heading.Level != p.toggleHeadingLevel {
+			blockBuilders = append(blockBuilders, p.ToBlocks(nodes[i])...)
+			continue
+		}
+
+		sectionEnd := i + 1
+		for ; sectionEnd < len(nodes); sectionEnd++ {
+			if isSectionEnd(nodes[sectionEnd], heading.Level) {
+				break
+			}
+		}
+
+		children := make(NtBlockBuilders, 0)
+		for _, child := range nodes[i+1 : sectionEnd] {
+			children = append(children, p.ToBlocks(child)...)
+		}
+
+		headingBuilders := p.ToBlocks(heading)
+		for _, hb := range headingBuilders {
+			hb.DecorateWith(func(source []byte, block nt.Block) {
+				nestIntoHeading(block, children.Build(source))
+			})
+		}
+		blockBuilders = append(blockBuilders, headingBuilders...)
+
+		i = sectionEnd - 1
+	}
+
+	return blockBuilders
+}
+
+// isSectionEnd returns true if the given node finishes the section of a heading of the given level
+// Footnotes are gathered at the end of the document, so they don't belong to the last section
+func isSectionEnd(node mdast.Node, level int) bool {
+	if node.Kind() == mdastx.KindFootnoteList {
+		return true
+	}
+
+	heading, ok := node.(*mdast.Heading)
+	return ok && heading.Level <= level
+}
+
+// nestIntoHeading puts given children into the given heading making it toggleable
+// (Headings rendered as paragraphs simply get the children)
+func nestIntoHeading(block nt.Block, children nt.Blocks) {
+	switch v := block.(type) {
+	case *nt.Heading1Block:
+		v.Heading1.IsToggleable = true
+		v.Heading1.Children = children
+	case *nt.Heading2Block:
+		v.Heading2.IsToggleable = true
+		v.Heading2.Children = children
+	case *nt.Heading3Block:
+		v.Heading3.IsToggleable = true
+		v.Heading3.Children = children
+	case *nt.ParagraphBlock:
+		v.Paragraph.Children = children
+	}
+}
+
+// DefaultTitle is the page title of documents that have no title
+const DefaultTitle = "Unnamed Document"
+
+// preparePageProperties takes the first Heading 1 block out of given blocks and makes it the page title
+// (the given default title if there is no Heading 1 block)
+// Note: mapping of Markdown H1-H6 into Notion headings is configured via WithHeadingStrategy
+func preparePageProperties(blocks nt.Blocks, defaultTitle string) (nt.Blocks, nt.Properties) {
+	var pageTitle []nt.RichText
+	for i, block := range blocks {
+		if block.GetType() == nt.BlockTypeHeading1 {
+			heading := block.(*nt.Heading1Block) // nolint:errcheck
+			pageTitle = heading.Heading1.RichText
+			// delete this block, its children (content of a toggle heading section) are moved up in its place
+			blocks = slices.Concat(blocks[:i:i], heading.Heading1.Children, blocks[i+1:])
+			break
+		}
+	}
+	if len(pageTitle) == 0 {
+		pageTitle = []nt.RichText{
+			*nt.NewTextRichText(defaultTitle),
+		}
+	}
+
+	return blocks, nt.Properties{
+		string(nt.PropertyConfigTypeTitle): nt.TitleProperty{
+			Title: pageTitle,
+		},
+	}
+}
+
+// IsConvertableToRichText returns true if given Markdown AST node is convertable directly into notion RichText
+// Nodes of registered handlers are convertable if they have a rich text handler (see WithRichTextHandler)
+func (p *Parser) IsConvertableToRichText(node mdast.Node) bool {
+	if _, ok := p.richTextHandlers[node.Kind()]; ok {
+		return true
+	}
+	if _, ok := p.blockHandlers[node.Kind()]; ok {
+		return false
+	}
+
+	switch node.Kind() {
+	case
+		mdast.KindText, mdast.KindString, mdast.KindParagraph,
+		mdast.KindCodeBlock, mdast.KindFencedCodeBlock, mdast.KindCodeSpan,
+		mdast.KindEmphasis, mdastx.KindStrikethrough,
+		mdast.KindRawHTML, mdast.KindHTMLBlock,
+		mdast.KindListItem, mdast.KindAutoLink,
+		mdastx.KindFootnoteLink, mdastx.KindFootnoteBacklink,
+		KindInlineHTML:
+		return true
+
+	case mdast.KindLink, mdast.KindTextBlock:
+		// ensure that it doesn't have any block children inside
+		// those are Image and TaskCheckBox
+		nonCovertableChildrenKinds := map[mdast.NodeKind]struct{}{
+			mdast.KindImage:         {},
+			mdastx.KindTaskCheckBox: {},
+		}
+		if node.FirstChild() != nil {
+			if _, ok := nonCovertableChildrenKinds[node.FirstChild().Kind()]; ok {
+				return false
+			}
+
+			// should we recursively check children?
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// ExtractRichTexts extract all richtexts for a given node
+// It does work ONLY for nodes that can be handled purely via Notion's RichTexts
+// Use IsConvertableToRichText to check it.
+// Returned builders are bound to the innermost nodes they're converted from (see SourceNode)
+func (p *Parser) ExtractRichTexts(node mdast.Node) (result NtRichTextBuilders) {
+	defer func() {
+		for _, builder := range result {
+			if builder != nil && builder.node == nil {
+				builder.node = node
+			}
+		}
+	}()
+
+	if handler, ok := p.richTextHandlers[node.Kind()]; ok {
+		if richTexts := handler(p, node); richTexts != nil {
+			return richTexts
+		}
+	}
+
+	// Backlinks make sense only for HTML (no anchors to link back to in Notion), so they're omitted
+	// Empty inline HTML elements (e.g. `<b></b>`) have nothing to show
+	if node.Kind() == mdastx.KindFootnoteBacklink || node.Kind() == KindInlineHTML && node.ChildCount() == 0 {
+		return NtRichTextBuilders{}
+	}
+
+	if node.ChildCount() == 0 {
+		return NtRichTextBuilders{toRichText(node)}
+	}
+
+	richTexts := make(NtRichTextBuilders, 0)
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		richTexts = append(richTexts, decorateRichTexts(
+			node,
+			p.ExtractRichTexts(child),
+		)...)
+	}
+	return richTexts
+}
+
+// toRichText returns a NtRichTextBuilder for a given node
+// RichTextConstructor then can be called with a given source to construct a ready-to-use notion RichText object
+func toRichText(node mdast.Node) *NtRichTextBuilder {
+	switch v := node.(type) {
+	case *mdast.Heading:
+		return NewNtRichTextBuilder(func(source []byte) *nt.RichText {
+			return nt.NewTextRichText(string(contentFromLines(v, source)))
+		})
+	case *mdast.Text:
+		return NewNtRichTextBuilder(func(source []byte) *nt.RichText {
+			// Backslash escapes (e.g. `\*`) stand for the characters themselves, except in code spans
+			if v.Parent() != nil && v.Parent().Kind() == mdast.KindCodeSpan {
+				return nt.NewTextRichText(string(v.Value(source)))
+			}
+			return nt.NewTextRichText(string(mdutil.UnescapePunctuations(v.Value(source))))
+		})
+	case *mdast.FencedCodeBlock, *mdast.CodeBlock:
+		return NewNtRichTextBuilder(func(source []byte) *nt.RichText {
+			return nt.NewTextRichText(string(contentFromLines(v, source)))
+		})
+	case *mdast.AutoLink:
+		return NewNtRichTextBuilder(func(source []byte) *nt.RichText {
+			link := string(v.URL(source))
+			label := string(v.Label(source))
+
+			return nt.NewLinkRichText(label, link)
+		})
+	case *mdast.Link:
+		return NewNtRichTextBuilder(func(source []byte) *nt.RichText {
+			link := string(v.Destination)
+			label := cmp.Or(string(v.Title), link)
+
+			return nt.NewLinkRichText(label, link)
+		})
+	case *mdast.String:
+		return NewNtRichTextBuilder(func(_ []byte) *nt.RichText {
+			return nt.NewTextRichText(string(v.Value))
+		})
+	case *mdast.RawHTML:
+		// Paired inline tags are already gathered into InlineHTML nodes
+		// so here we have only line breaks, comments, self-closing and unpaired tags: all but line breaks are stripped
+		return NewNtRichTextBuilder(func(source []byte) *nt.RichText {
+			if !isHTMLBreak(string(contentFromSegments(v.Segments, source))) {
+				return nil
+			}
+			return nt.NewTextRichText("\n")
+		})
+	case *mdast.HTMLBlock:
+		return NewNtRichTextBuilder(func(source []byte) *nt.RichText {
+			content := html2notion(
+				string(contentFromLines(v, source)),
+			)
+			return nt.NewTextRichText(content)
+		})
+	case *mdast.TextBlock:
+		return NewNtRichTextBuilder(func(source []byte) *nt.RichText {
+			return nt.NewTextRichText(string(contentFromLines(v, source)))
+		})
+	case *mdastx.FootnoteLink:
+		return NewNtRichTextBuilder(func(_ []byte) *nt.RichText {
+			return nt.NewTextRichText(footnoteMarker(v.Index))
+		})
+	default:
+		return nil
+	}
+}
+
+// ToBlocks converts given MD ast node into series of Notion Blocks
+// Registered handlers (see WithBlockHandler) take precedence over the built-in conversion
+// Returned builders are bound to the given node, unless a more specific one is already known (see SourceNode)
+// nolint: gocyclo // Will be OK after further refactor
+func (p *Parser) ToBlocks(node mdast.Node) (result NtBlockBuilders) {
+	// Thoughts: First switch is used when ToBlocks was called from children handling (recursion)
+	// can we optimize it somehow?
+	defer func() {
+		if r := recover(); r != nil {
+			p.report(node, "can't be converted: %v", r)
+			result = NtBlockBuilders{p.handleUnknownNode(node)}
+		}
+		for _, builder := range result {
+			p.bindSource(builder, node)
+		}
+	}()
+
+	if handler, ok := p.blockHandlers[node.Kind()]; ok {
+		if blocks := handler(p, node); blocks != nil {
+			return blocks
+		}
+	}
+
+	// Pure flattening first:
+	switch node.Kind() {
+	case mdast.KindHeading:
+		return p.handleHeading(node)
+	case mdast.KindCodeBlock, mdast.KindFencedCodeBlock:
+		return NtBlockBuilders{
+			NewNtBlockBuilder(func(source []byte) nt.Block {
+				var language string
+				if codeBlock, ok := node.(*mdast.FencedCodeBlock); ok {
+					language = string(codeBlock.Language(source))
+				}
+				richTexts := p.ExtractRichTexts(node)
+
+				return nt.NewCodeBlock(nt.Code{
+					RichText: richTexts.Build(source),
+					Language: cmp.Or(language, p.defaultCodeLanguage),
+				})
+			}),
+		}
+	case mdast.KindThematicBreak:
+		return NtBlockBuilders{
+			NewNtBlockBuilder(func(_ []byte) nt.Block {
+				return nt.NewDividerBlock()
+			}),
+		}
+	case mdast.KindImage:
+		return p.handleImage(node)
+	case mdastx.KindTable: // Use the extension AST for the Table node
+		return p.handleTable(node)
+	case mdast.KindHTMLBlock:
+		return p.handleHTMLBlock(node)
+	case KindDetails:
+		return p.handleDetails(node)
+	case KindHTMLFragment:
+		return p.handleHTMLFragment(node)
+	case mdast.KindTextBlock:
+		return p.handleTextBlock(node)
+	case mdastx.KindFootnoteList:
+		return p.handleFootnoteList(node)
+	}
+
+	if node.ChildCount() == 0 {
+		panic("Empty node on top-level ToBlocks call")
+	}
+
+	// Nested blocks are required:
+	switch node.Kind() {
+	case mdast.KindParagraph:
+		// similar to BlockQuote - should be handled in a shared way
+		innerTexts := make(NtRichTextBuilders, 0)
+		innerBlocks := make(NtBlockBuilders, 0)
+		// text following an inner block (e.g. between images of a badge row) becomes a nested paragraph
+		var trailingTexts NtRichTextBuilders
+		flushTrailingTexts := func() {
+			if len(trailingTexts) > 0 {
+				innerBlocks = append(innerBlocks, newTrailingParagraph(trailingTexts))
+				trailingTexts = nil
+			}
+		}
+		for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+			// if it's convertable to rich text, and we didn't handle any blocks yet, we're OK to flatten
+			switch convertable := p.IsConvertableToRichText(child); {
+			case convertable && len(innerBlocks) == 0:
+				innerTexts = append(innerTexts, p.ExtractRichTexts(child)...)
+			case convertable:
+				trailingTexts = append(trailingTexts, p.ExtractRichTexts(child)...)
+			default:
+				flushTrailingTexts()
+				innerBlocks = append(innerBlocks, p.ToBlocks(child)...)
+			}
+		}
+		flushTrailingTexts()
+		return NtBlockBuilders{
+			NewNtBlockBuilder(func(source []byte) nt.Block {
+				return nt.NewParagraphBlock(nt.Paragraph{
+					RichText: innerTexts.Build(source),
+					Children: innerBlocks.Build(source),
+				})
+			}),
+		}
+	case mdast.KindBlockquote:
+		return p.handleBlockquote(node)
+	case mdast.KindList:
+		return p.handleList(node)
+	case mdast.KindLink:
+		return p.handleBlockLink(node)
+	case mdast.KindTextBlock:
+		return p.handleTextBlock(node)
+	case mdastx.KindDefinitionList:
+		return p.handleDefinitionList(node)
+	}
+
+	panic(fmt.Sprintf("unhandled node type: %s", node.Kind().String()))
+}
+
+// newTrailingParagraph returns a paragraph of the given rich texts, blank ones are skipped (nil block)
+func newTrailingParagraph(richTexts NtRichTextBuilders) *NtBlockBuilder {
+	return NewNtBlockBuilder(func(source []byte) nt.Block {
+		paragraph := nt.NewParagraphBlock(nt.Paragraph{RichText: richTexts.Build(source)})
+		if strings.TrimSpace(paragraph.GetRichTextString()) == "" {
+			return nil
+		}
+		return paragraph
+	})
+}
+
+// handleHeading handles custom logic of Markdown->Notion Headings
+// Although in MD mdast.Heading can have children,
+// In notion it's a flattened list of RichTexts
+// Note: toggleable headings (with nested section content) are handled in handleSections
+func (p *Parser) handleHeading(node mdast.Node) NtBlockBuilders {
+	heading := node.(*mdast.Heading) // nolint:errcheck
+	headingLevel := heading.Level
+	richTexts := p.ExtractRichTexts(node)
+
+	switch p.headingStrategy {
+	case HeadingStrategyShift:
+		// Otherwise headings are clamped
+		if p.shiftHeadings && headingLevel > 1 {
+			headingLevel--
+		}
+	case HeadingStrategyParagraph:
+		if headingLevel > 3 {
+			for _, rt := range richTexts {
+				rt.DecorateWith(boldDecorator)
+			}
+			return NtBlockBuilders{NewNtBlockBuilder(func(source []byte) nt.Block {
+				return nt.NewParagraphBlock(nt.Paragraph{RichText: richTexts.Build(source)})
+			})}
+		}
+	}
+	headingLevel = min(headingLevel, 3)
+
+	return NtBlockBuilders{NewNtBlockBuilder(func(source []byte) nt.Block {
+		return nt.NewHeadingBlock(
+			nt.Heading{RichText: richTexts.Build(source)},
+			headingLevel,
+		)
+	})}
+}
+
+func (p *Parser) handleImage(node mdast.Node, decorations ...RichTextDecorator) NtBlockBuilders {
+	captionRichTexts := NtRichTextBuilders{}
+	if child := node.FirstChild(); child != nil {
+		captionRichTexts = p.ExtractRichTexts(child)
+	}
+	if len(captionRichTexts) > 0 && len(decorations) > 0 {
+		for _, rt := range captionRichTexts {
+			for _, d := range decorations {
+				rt.DecorateWith(d)
+			}
+		}
+	}
+
+	return NtBlockBuilders{
+		NewNtBlockBuilder(func(source []byte) nt.Block {
+			return nt.NewImageBlock(nt.Image{
+				Type: nt.FileTypeExternal,
+				External: &nt.FileObject{
+					URL: string(node.(*mdast.Image).Destination), // nolint:errcheck
+				},
+				Caption: captionRichTexts.Build(source),
+			})
+		}),
+	}
+}
+
+// handleTable handles custom logic of Markdown->Notion tables
+// Nothing special here, just custom defining of rows, headers, and cells
+func (p *Parser) handleTable(node mdast.Node) NtBlockBuilders {
+	table := node.(*mdastx.Table) // nolint:errcheck
+
+	// Collect headers and rows
+	headers := make([]NtRichTextBuilders, 0)
+	rows := make([][]NtRichTextBuilders, 0)
+
+	// TODO: support recursive tables?
+
+	// Iterate over the table's children to extract headers and rows
+	for tr := table.FirstChild(); tr != nil; tr = tr.NextSibling() {
+		switch tr.Kind() {
+		case mdastx.KindTableHeader:
+			// Collect headers
+			for th := tr.FirstChild(); th != nil; th = th.NextSibling() {
+				// TODO: is it possible in the Header to have nested blocks?
+				headers = append(headers, p.ExtractRichTexts(th))
+			}
+
+		case mdastx.KindTableRow:
+			// Collect each row's cells
+			row := make([]NtRichTextBuilders, 0)
+			for td := tr.FirstChild(); td != nil; td = td.NextSibling() {
+				// TODO: we need to handle any nested blocks inside tables as well
+				row = append(row, p.ExtractRichTexts(td))
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	// Create Notion table block
+	return NtBlockBuilders{
+		NewNtBlockBuilder(func(source []byte) nt.Block {
+			builtRows := make([][][]nt.RichText, 0, len(rows)+1)
+			if len(headers) > 0 {
+				headerRow := make([][]nt.RichText, len(headers))
+				for i, header := range headers {
+					headerRow[i] = header.Build(source)
+				}
+				builtRows = append(builtRows, headerRow)
+			}
+			for _, row := range rows {
+				builtRow := make([][]nt.RichText, len(row))
+				for i, cell := range row {
+					builtRow[i] = cell.Build(source)
+				}
+				builtRows = append(builtRows, builtRow)
+			}
+
+			//HasRowHeader:  false, // TODO(amberpixels) is this possible to be known from markdown?
+			return newTableBlock(len(headers), true, false, builtRows)
+		}),
+	}
+}
+
+// newTableBlock constructs Notion table block of the given width from the given rows of cells
+// Rows that are shorter than the table are padded with empty cells (Notion requires all rows to be of the table width)
+func newTableBlock(width int, hasColumnHeader, hasRowHeader bool, rows [][][]nt.RichText) *nt.TableBlock {
+	tableBlock := nt.NewTableBlock(nt.Table{
+		TableWidth:      width,
+		HasColumnHeader: hasColumnHeader,
+		HasRowHeader:    hasRowHeader,
+		Children:        nt.Blocks{}, // will be populated below
+	})
+
+	for _, row := range rows {
+		tableRow := nt.TableRow{
+			Cells: make([][]nt.RichText, 0, width),
+		}
+		tableRow.Cells = append(tableRow.Cells, row...)
+		for len(tableRow.Cells) < width {
+			tableRow.Cells = append(tableRow.Cells, []nt.RichText{})
+		}
+		tableBlock.Table.Children = append(tableBlock.Table.Children, nt.NewTableRowBlock(tableRow))
+	}
+
+	return tableBlock
+}
+
+// handleHTMLBlock handles custom logic of Markdown->Notion HTML blocks
+// Notion doesn't support HTML in rich-text so we have to convert it manually into Notion blocks
+// Supported HTML blocks (paragraphs, images, etc) are converted as HTMLFragment nodes (see handleHTMLFragment)
+// Others are kept as RAW html (no parsing), rendered as code blocks or dropped (see WithHTMLStrategy)
+// TODO: support HTML lists?
+func (p *Parser) handleHTMLBlock(node mdast.Node) NtBlockBuilders {
+	switch p.htmlStrategy {
+	case HTMLStrategySkip:
+		return NtBlockBuilders{}
+	case HTMLStrategyCode:
+		return NtBlockBuilders{
+			NewNtBlockBuilder(func(source []byte) nt.Block {
+				content := sanitizeMarkdownLintComments(string(htmlBlockContent(node.(*mdast.HTMLBlock), source))) // nolint:errcheck
+				if content == "" {
+					return nil
+				}
+				p.report(node, "HTML block is not supported, it's kept as HTML code")
+				return nt.NewCodeBlock(nt.Code{
+					RichText: splitRichText(*nt.NewTextRichText(content)),
+					Language: "html",
+				})
+			}),
+		}
+	}
+
+	richTexts := p.ExtractRichTexts(node)
+
+	return NtBlockBuilders{
+		NewNtBlockBuilder(func(source []byte) nt.Block {
+			// Weak solution but fine for now
+			saneContent := make([]nt.RichText, 0)
+			for _, rt := range richTexts.Build(source) {
+				cleaned := sanitizeMarkdownLintComments(rt.PlainText)
+				if cleaned == "" {
+					continue
+				}
+				rt.PlainText = cleaned
+				rt.Text.Content = cleaned
+				saneContent = append(saneContent, rt)
+			}
+			if len(saneContent) > 0 {
+				p.report(node, "HTML block is not supported, it's kept as raw text")
+			}
+
+			return nt.NewParagraphBlock(nt.Paragraph{
+				RichText: saneContent,
+			})
+		}),
+	}
+}
+
+// handleBlockquote handles custom logic of Markdown->Notion Blockquotes
+// Notion's Blockquote is a container that has both mandatory rich-text content and children
+// Mandatory rich-text makes an issue if in Markdown you had a blockquote with a heading as a first child
+// (As heading is a block, can't be fully represented in rich-text)
+// GitHub-style alerts (`> [!NOTE]`, `> [!WARNING]`, etc.) are converted into Notion's callouts
+func (p *Parser) handleBlockquote(node mdast.Node) NtBlockBuilders {
+	// TODO: handle blockquotes better
+
+	// Inline nodes of the leading paragraph are kept separately, as they can start with an alert marker
+	leadNodes := make([]mdast.Node, 0)
+	leadTexts := make([]NtRichTextBuilders, 0)
+	if first, ok := node.FirstChild().(*mdast.Paragraph); ok {
+		for child := first.FirstChild(); child != nil; child = child.NextSibling() {
+			leadNodes = append(leadNodes, child)
+			leadTexts = append(leadTexts, p.ExtractRichTexts(child))
+		}
+	}
+
+	innerTexts := make(NtRichTextBuilders, 0)
+	innerBlocks := make(NtBlockBuilders, 0)
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		if child == node.FirstChild() && len(leadNodes) > 0 {
+			continue
+		}
+
+		// if it's convertable to rich text and we didn't handle any blocks yet, we're OK to flatten
+		// as soon as we met an inner block, all further children are considered as blocks as well
+		if p.IsConvertableToRichText(child) && len(innerBlocks) == 0 {
+			innerTexts = append(innerTexts, p.ExtractRichTexts(child)...)
+		} else {
+			innerBlocks = append(innerBlocks, p.ToBlocks(child)...)
+		}
+	}
+
+	return NtBlockBuilders{
+		NewNtBlockBuilder(func(source []byte) nt.Block {
+			alert, markerLength := detectAlertMarker(leadNodes, source)
+
+			richTexts := make(NtRichTextBuilders, 0)
+			for _, rts := range leadTexts[markerLength:] {
+				richTexts = append(richTexts, rts...)
+			}
+			richTexts = append(richTexts, innerTexts...)
+
+			if alert != nil {
+				return nt.NewCalloutBlock(nt.Callout{
+					RichText: richTexts.Build(source),
+					Icon:     emojiIcon(alert.emoji),
+					Children: innerBlocks.Build(source),
+					Color:    alert.color.String(),
+				})
+			}
+
+			return nt.NewQuoteBlock(nt.Quote{
+				RichText: richTexts.Build(source),
+				Children: innerBlocks.Build(source),
+			})
+		}),
+	}
+}
+
+// githubAlert describes how a GitHub-style alert is represented as a Notion callout
+type githubAlert struct {
+	emoji string
+	color nt.Color
+}
+
+var githubAlerts = map[string]githubAlert{
+	"NOTE":      {emoji: "ℹ️", color: nt.ColorBlueBackground},
+	"TIP":       {emoji: "💡", color: nt.ColorGreenBackground},
+	"IMPORTANT": {emoji: "❗", color: nt.ColorPurpleBackground},
+	"WARNING":   {emoji: "⚠️", color: nt.ColorYellowBackground},
+	"CAUTION":   {emoji: "🛑", color: nt.ColorRedBackground},
+}
+
+var githubAlertRegex = regexp.MustCompile(`^\[!([A-Za-z]+)\]$`)
+
+// detectAlertMarker checks if given inline nodes (of a blockquote's leading paragraph) start with
+// a GitHub-style alert marker (e.g. `[!NOTE]`) placed on its own line.
+// It returns the detected alert and the number of nodes the marker takes (nil and 0 if there is no marker)
+func detectAlertMarker(nodes []mdast.Node, source []byte) (*githubAlert, int) {
+	var firstLine string
+	for i, node := range nodes {
+		text, ok := node.(*mdast.Text)
+		if !ok {
+			return nil, 0
+		}
+		firstLine += string(text.Value(source))
+
+		if text.SoftLineBreak() || text.HardLineBreak() || i == len(nodes)-1 {
+			match := githubAlertRegex.FindStringSubmatch(strings.TrimSpace(firstLine))
+			if match == nil {
+				return nil, 0
+			}
+			alert, ok := githubAlerts[strings.ToUpper(match[1])]
+			if !ok {
+				return nil, 0
+			}
+			return &alert, i + 1
+		}
+	}
+
+	return nil, 0
+}
+
+// handleList processes a markdown list and returns appropriate Notion blocks
+func (p *Parser) handleList(node mdast.Node) NtBlockBuilders {
+	list := node.(*mdast.List) // nolint:errcheck
+
+	// Check if list is bulleted or numbered
+	var bulletted bool
+	if list.Marker == '-' || list.Marker == '+' || list.Marker == '*' {
+		bulletted = true
+	}
+
+	blocks := make(NtBlockBuilders, 0)
+	for child := list.FirstChild(); child != nil; child = child.NextSibling() {
+		builder := p.handleListItem(child, bulletted)
+		p.bindSource(builder, child)
+		blocks = append(blocks, builder)
+	}
+
+	return blocks
+}
+
+func (p *Parser) handleBlockLink(node mdast.Node) NtBlockBuilders {
+	// Notion doesn't support block links natively
+	// In future it can be achieved with a custom Notion block
+	// For now we only support Images inside links, via linkifying the image caption
+	link := node.(*mdast.Link) // nolint:errcheck
+	image, ok := link.FirstChild().(*mdast.Image)
+	if !ok {
+		return nil
+	}
+
+	return p.handleImage(image, linkDecorator(string(link.Destination)))
+}
+
+func (p *Parser) handleTextBlock(node mdast.Node) NtBlockBuilders {
+	richTexts := p.ExtractRichTexts(node)
+
+	return NtBlockBuilders{
+		NewNtBlockBuilder(func(source []byte) nt.Block {
+			rts := nonEmptyRichTexts(richTexts.Build(source))
+			if len(rts) == 0 {
+				return nil
+			}
+
+			return nt.NewParagraphBlock(nt.Paragraph{
+				RichText: rts,
+			})
+		}),
+	}
+}
+
+// handleListItem handles MD's list item and its children
+// List Item on markdown can have children. For notion - first child is usually a RichText
+// Other children are built as nested blocks
+// Exception is TaskItem. On Notion it's not a ListItem at all. It's just a ToDoBlock
+func (p *Parser) handleListItem(node mdast.Node, bulletted bool) *NtBlockBuilder {
+	// Extract RichText (from first child)
+	mainContent := make(NtRichTextBuilders, 0)
+	if child := node.FirstChild(); child != nil {
+		// If we get here, it's safe to convert to rich text
+		if p.IsConvertableToRichText(child) {
+			mainContent = p.ExtractRichTexts(child)
+		}
+	}
+
+	var children NtBlockBuilders
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		if len(mainContent) > 0 && child.PreviousSibling() == nil { // skip main content
+			continue
+		}
+
+		switch child.Kind() {
+		case mdast.KindTextBlock: // TASK items are hidden inside text blocks
+			for grandChild := child.FirstChild(); grandChild != nil; {
+				if grandChild.Kind() == mdastx.KindTaskCheckBox {
+					return p.handleTaskItem(child)
+				}
+				break
+			}
+
+		default:
+			children = append(children, p.ToBlocks(child)...)
+		}
+	}
+
+	return NewNtBlockBuilder(func(source []byte) nt.Block {
+		li := nt.ListItem{
+			RichText: mainContent.Build(source),
+			Children: children.Build(source),
+		}
+
+		if bulletted {
+			return nt.NewBulletedListItemBlock(li)
+		} else {
+			return nt.NewNumberedListItemBlock(li)
+		}
+	})
+}
+
+// handleTaskItem handles given node to ensure it's a markdown task item
+// For this it should have first child as a checkbox and then its content
+func (p *Parser) handleTaskItem(node mdast.Node) *NtBlockBuilder {
+	if node == nil || node.FirstChild() == nil {
+		return nil
+	}
+	checkbox, ok := node.FirstChild().(*mdastx.TaskCheckBox)
+	if !ok {
+		return nil
+	}
+
+	// Get the text content that follows the checkbox
+	labels := make(NtRichTextBuilders, 0)
+	for next := checkbox.NextSibling(); next != nil; next = next.NextSibling() {
+		if p.IsConvertableToRichText(next) {
+			labels = append(labels, p.ExtractRichTexts(next)...)
+		}
+	}
+
+	return NewNtBlockBuilder(func(source []byte) nt.Block {
+		return nt.NewToDoBlock(nt.ToDo{
+			Checked:  checkbox.IsChecked,
+			RichText: labels.Build(source),
+		})
+	})
+}
+
+// handleDefinitionList handles definition lists (PHP Markdown Extra)
+// Notion has nothing similar, so each term becomes a bold paragraph (or a toggle, depending on DefinitionListStyle)
+// and all descriptions that follow the term become its nested children
+func (p *Parser) handleDefinitionList(node mdast.Node) NtBlockBuilders {
+	type definition struct {
+		node         mdast.Node
+		term         NtRichTextBuilders
+		descriptions NtBlockBuilders
+	}
+
+	definitions := make([]*definition, 0)
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		switch child.Kind() {
+		case mdastx.KindDefinitionTerm:
+			term := p.ExtractRichTexts(child)
+			if p.definitionListStyle != DefinitionListStyleToggle {
+				for _, rt := range term {
+					rt.DecorateWith(boldDecorator)
+				}
+			}
+			definitions = append(definitions, &definition{node: child, term: term})
+
+		case mdastx.KindDefinitionDescription:
+			if len(definitions) == 0 { // should never happen, but let's be safe
+				definitions = append(definitions, &definition{})
+			}
+			last := definitions[len(definitions)-1]
+			for grandChild := child.FirstChild(); grandChild != nil; grandChild = grandChild.NextSibling() {
+				last.descriptions = append(last.descriptions, p.ToBlocks(grandChild)...)
+			}
+		}
+	}
+
+	style := p.definitionListStyle
+	blocks := make(NtBlockBuilders, 0, len(definitions))
+	for _, def := range definitions {
+		builder := NewNtBlockBuilder(func(source []byte) nt.Block {
+			if style == DefinitionListStyleToggle {
+				return nt.NewToggleBlock(nt.Toggle{
+					RichText: def.term.Build(source),
+					Children: def.descriptions.Build(source),
+				})
+			}
+
+			return nt.NewParagraphBlock(nt.Paragraph{
+				RichText: def.term.Build(source),
+				Children: def.descriptions.Build(source),
+			})
+		})
+		if def.node != nil {
+			p.bindSource(builder, def.node)
+		}
+		blocks = append(blocks, builder)
+	}
+
+	return blocks
+}
+
+// handleFootnoteList handles footnote definitions (that are gathered by goldmark at the end of the document)
+// Depending on the configured FootnoteStyle footnotes are rendered as a "Footnotes" section, callouts or toggles
+func (p *Parser) handleFootnoteList(node mdast.Node) NtBlockBuilders {
+	blocks := make(NtBlockBuilders, 0)
+	if p.footnoteStyle == FootnoteStyleSection && node.ChildCount() > 0 {
+		blocks = append(blocks,
+			NewNtBlockBuilder(func(_ []byte) nt.Block {
+				return nt.NewDividerBlock()
+			}),
+			NewNtBlockBuilder(func(_ []byte) nt.Block {
+				return nt.NewHeading2Block(nt.Heading{
+					RichText: []nt.RichText{*nt.NewTextRichText("Footnotes")},
+				})
+			}),
+		)
+	}
+
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		if footnote, ok := child.(*mdastx.Footnote); ok {
+			builder := p.handleFootnote(footnote)
+			p.bindSource(builder, footnote)
+			blocks = append(blocks, builder)
+		}
+	}
+
+	return blocks
+}
+
+// handleFootnote handles a single footnote definition
+// Its first paragraph becomes the rich text of the resulting block, the rest of its content become children
+func (p *Parser) handleFootnote(footnote *mdastx.Footnote) *NtBlockBuilder {
+	mainContent := make(NtRichTextBuilders, 0)
+	children := make(NtBlockBuilders, 0)
+	for child := footnote.FirstChild(); child != nil; child = child.NextSibling() {
+		// Backlinks make sense only for HTML, they're placed right into the footnote if it doesn't end with a paragraph
+		if child.Kind() == mdastx.KindFootnoteBacklink {
+			continue
+		}
+		if child.PreviousSibling() == nil && p.IsConvertableToRichText(child) {
+			mainContent = p.ExtractRichTexts(child)
+			continue
+		}
+		children = append(children, p.ToBlocks(child)...)
+	}
+
+	marker := footnoteMarker(footnote.Index)
+	style := p.footnoteStyle
+
+	return NewNtBlockBuilder(func(source []byte) nt.Block {
+		switch style {
+		case FootnoteStyleCallout:
+			richTexts := append([]nt.RichText{*nt.NewTextRichText(marker + " ")}, mainContent.Build(source)...)
+			return nt.NewCalloutBlock(nt.Callout{
+				RichText: richTexts,
+				Icon:     emojiIcon("📝"),
+				Children: children.Build(source),
+				Color:    nt.ColorGrayBackground.String(),
+			})
+		case FootnoteStyleToggle:
+			return nt.NewToggleBlock(nt.Toggle{
+				RichText: []nt.RichText{*nt.NewTextRichText(fmt.Sprintf("Footnote %d", footnote.Index))},
+				Children: append(
+					nt.Blocks{nt.NewParagraphBlock(nt.Paragraph{RichText: mainContent.Build(source)})},
+					children.Build(source)...,
+				),
+			})
+		default:
+			return nt.NewNumberedListItemBlock(nt.ListItem{
+				RichText: mainContent.Build(source),
+				Children: children.Build(source),
+			})
+		}
+	})
+}
+
+// handleUnknownNode converts the node that can't be converted into a plain paragraph of its text (if it has any)
+// Note: the node is reported in diagnostics
+func (p *Parser) handleUnknownNode(node mdast.Node) *NtBlockBuilder {
+	return NewNtBlockBuilder(func(source []byte) nt.Block {
+		content := toRichText(node)
+		if content == nil {
+			return nil
+		}
+		built := content.Build(source)
+		if built == nil {
+			return nil
+		}
+
+		return nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{*built},
+			Children: nt.Blocks{},
+		})
+	})
+}
+
+func decorateRichTexts(parent mdast.Node, richTexts NtRichTextBuilders) NtRichTextBuilders {
+	// TODO" make immutable function
+	switch v := parent.(type) {
+	case *mdastx.Strikethrough:
+		for i := range richTexts {
+			richTexts[i].DecorateWith(strikethroughDecorator)
+		}
+	case *mdast.Emphasis:
+		for i := range richTexts {
+			if v.Level == 1 {
+				richTexts[i].DecorateWith(italicDecorator)
+			} else {
+				richTexts[i].DecorateWith(boldDecorator)
+			}
+		}
+	case *mdast.CodeSpan:
+		// Adding t.Annotations = code:true for each child
+		for i := range richTexts {
+			richTexts[i].DecorateWith(codeDecorator)
+		}
+
+	case *mdast.Link:
+		for i := range richTexts {
+			richTexts[i].DecorateWith(linkDecorator(string(v.Destination)))
+		}
+	case *InlineHTML:
+		if decorator := inlineHTMLDecorator(v); decorator != nil {
+			for i := range richTexts {
+				richTexts[i].DecorateWith(decorator)
+			}
+		}
+	}
+
+	return richTexts
+}
+
+var markdownLintRegex = regexp.MustCompile(`(?i)<!--\s*markdownlint-.*?-->`)
+
+// sanitizeMarkdownLintComments checks if the content is a markdownlint-disable comment
+func sanitizeMarkdownLintComments(content string) string {
+	return strings.TrimSpace(markdownLintRegex.ReplaceAllString(content, ""))
+}
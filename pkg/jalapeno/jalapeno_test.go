@@ -0,0 +1,2680 @@
+package jalapeno_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amberpixels/peppers/internal/testhelpers"
+	"github.com/amberpixels/peppers/pkg/jalapeno"
+	nt "github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	mdast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Global parser instance for all tests (with the default goldmark configuration)
+var parserInstance = jalapeno.NewParser(nil)
+
+func TestParser_ParseBlocks(t *testing.T) {
+	type AssertFunc = func(t *testing.T, source string, expectedBlocks nt.Blocks)
+	type TestFunc = func(name string, source string, expectedBlocks nt.Blocks)
+
+	f, ff, xf, run := testhelpers.GenerateCases[TestFunc, AssertFunc](t, func(t *testing.T, source string, expectedBlocks nt.Blocks) {
+		blocks, _, err := parserInstance.ParseBlocks([]byte(source))
+
+		require.NoError(t, err, "Parsing failed")
+		assert.Len(t, blocks, len(expectedBlocks), "Generated blocks do not match expected blocks")
+		for i, b := range blocks {
+			assert.Equal(t, expectedBlocks[i].GetType(), b.GetType(),
+				fmt.Sprintf("Generated block[%d] do not match expected block", i))
+			assert.Equal(t, expectedBlocks[i], b,
+				fmt.Sprintf("Generated block[%d] do not match expected block", i))
+		}
+	})
+	_, _, _ = f, ff, xf
+
+	f("Empty document", "", nt.Blocks{})
+
+	// -----------------
+	// --- HEADINGS ----
+	// -----------------
+
+	f("Empty Heading1", "#", nt.Blocks{
+		nt.NewHeading1Block(nt.Heading{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText(""),
+			},
+		}),
+	})
+	f("Empty Heading1 with whitespace", "#   ", nt.Blocks{
+		nt.NewHeading1Block(nt.Heading{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText(""), // whitespace is trimmed
+			},
+		}),
+	})
+	f("Single Heading1", "# Heading", nt.Blocks{
+		nt.NewHeading1Block(nt.Heading{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Heading"),
+			},
+		}),
+	})
+	f("Alternative heading 1", "Heading\n===", nt.Blocks{
+		nt.NewHeading1Block(nt.Heading{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Heading"),
+			},
+		}),
+	})
+	f("Multiple-words Heading2", "## Heading Foobar", nt.Blocks{
+		nt.NewHeading2Block(nt.Heading{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Heading"),
+				*nt.NewTextRichText(" Foobar"),
+			},
+		}),
+	})
+
+	f("Alternative Heading2", "Heading Foobar\n--------------", nt.Blocks{
+		nt.NewHeading2Block(nt.Heading{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Heading"),
+				*nt.NewTextRichText(" Foobar"),
+			},
+		}),
+	})
+
+	f("Headings H1 to H4", `# Heading 1
+## Heading 2
+### Heading 3
+#### Heading 4`,
+		nt.Blocks{
+			nt.NewHeading1Block(nt.Heading{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Heading"),
+					*nt.NewTextRichText(" 1"),
+				},
+			}),
+			nt.NewHeading2Block(nt.Heading{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Heading"),
+					*nt.NewTextRichText(" 2"),
+				},
+			}),
+			nt.NewHeading3Block(nt.Heading{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Heading"),
+					*nt.NewTextRichText(" 3"),
+				},
+			}),
+			nt.NewHeading3Block(nt.Heading{ // H4 is converted to Heading3
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Heading"),
+					*nt.NewTextRichText(" 4"),
+				},
+			}),
+		})
+
+	f("Heading with inline code", "# This is `inline code`", nt.Blocks{
+		nt.NewHeading1Block(nt.Heading{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("This is "),
+				*nt.NewTextRichText("inline code").AnnotateCode(),
+			},
+		}),
+	})
+
+	f("Headings + Paragraph with code inline", `# Your Readme Package name
+
+## Overview
+`+"The `packageName` package provides a set of function for doing something useful.",
+		nt.Blocks{
+			nt.NewHeading1Block(nt.Heading{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Your Readme Package"),
+					*nt.NewTextRichText(" name"),
+				},
+			}),
+			nt.NewHeading2Block(nt.Heading{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Overview"),
+				},
+			}),
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("The "),
+					*nt.NewTextRichText("packageName").AnnotateCode(),
+					*nt.NewTextRichText(" package provides a set of function for doing something"),
+					*nt.NewTextRichText(" useful."),
+				},
+				Children: nt.Blocks{},
+			}),
+		})
+
+	// -------------------
+	// --- PARAGRAPHS ----
+	// -------------------
+
+	f("Empty paragram (with whitespace)", " \t\t ", nt.Blocks{})
+
+	f("Empty paragram (multiline with whitespace)", " \t\n\n\t ", nt.Blocks{})
+
+	f("Simple oneline paragraph", `Hello Foobar`, nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Hello"),
+				*nt.NewTextRichText(" Foobar"),
+			},
+			Children: nt.Blocks{},
+		}),
+	})
+
+	f("Simple multiline paragraph", `
+This is the first line
+this is the second
+and the third.
+`,
+		nt.Blocks{
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("This is the first"),
+					*nt.NewTextRichText(" line"),
+					*nt.NewTextRichText("this is the"),
+					*nt.NewTextRichText(" second"),
+					*nt.NewTextRichText("and the"),
+					*nt.NewTextRichText(" third."),
+				},
+				Children: nt.Blocks{},
+			}),
+		})
+
+	f("Paragraph with emphasis", `Hello **foobar**`, nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Hello "),
+				*nt.NewTextRichText("foobar").AnnotateBold(),
+			},
+			Children: nt.Blocks{},
+		}),
+	})
+
+	f("Paragraph with italic", `Hello *foobar*`, nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Hello "),
+				*nt.NewTextRichText("foobar").AnnotateItalic(),
+			},
+			Children: nt.Blocks{},
+		}),
+	})
+
+	f("Paragraph with strikethrough", `Hello ~~no foobar~~`, nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Hello "),
+				*nt.NewTextRichText("no foobar").AnnotateStrikethrough(),
+			},
+			Children: nt.Blocks{},
+		}),
+	})
+
+	f("Paragraph with different annotations", `This is a **bold text** 1, *italic text* 2, and ~~strikethrough text~~ 3.`, nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("This is a "),
+				*nt.NewTextRichText("bold text").AnnotateBold(),
+				*nt.NewTextRichText(" 1, "),
+				*nt.NewTextRichText("italic text").AnnotateItalic(),
+				*nt.NewTextRichText(" 2, and "),
+				*nt.NewTextRichText("strikethrough text").AnnotateStrikethrough(),
+				*nt.NewTextRichText(" 3."),
+			},
+			Children: nt.Blocks{},
+		}),
+	})
+
+	f("Paragraph with inline code", "This is `inline code`", nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("This is "),
+				*nt.NewTextRichText("inline code").AnnotateCode(),
+			},
+			Children: nt.Blocks{},
+		}),
+	})
+	f("Paragraph with escaped characters", "\\*not emphasis\\* and `kept \\*`", nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("*not emphasis* and "),
+				*nt.NewTextRichText("kept \\*").AnnotateCode(), // no escapes in code spans
+			},
+			Children: nt.Blocks{},
+		}),
+	})
+
+	// --------------
+	// --- LINKS ----
+	// --------------
+
+	f("Simple link", "[OpenAI](https://openai.com)", nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewLinkRichText("OpenAI", "https://openai.com"),
+			},
+			Children: nt.Blocks{},
+		}),
+	})
+
+	f("Simple inline link", "https://openai.com", nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewLinkRichText("https://openai.com", "https://openai.com"),
+			},
+			Children: nt.Blocks{},
+		}),
+	})
+
+	f("Simple explicit link", "<fake@gmail.com>", nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewLinkRichText("fake@gmail.com", "fake@gmail.com"),
+			},
+			Children: nt.Blocks{},
+		}),
+	})
+
+	f("Heading with non-inline link", `# Hello [OpenAI](https://openai.com)`, nt.Blocks{
+		nt.NewHeading1Block(nt.Heading{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Hello "),
+				*nt.NewLinkRichText("OpenAI", "https://openai.com"),
+			},
+		}),
+	})
+
+	f("Heading with inline link", `# Hello https://openai.com`, nt.Blocks{
+		nt.NewHeading1Block(nt.Heading{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Hello "),
+				*nt.NewLinkRichText("https://openai.com", "https://openai.com"),
+			},
+		}),
+	})
+
+	f("Heading2 with an inline link in brackets", `## Hello (https://openai.com)`, nt.Blocks{
+		nt.NewHeading2Block(nt.Heading{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Hello ("),
+				*nt.NewLinkRichText("https://openai.com", "https://openai.com"),
+				*nt.NewTextRichText(")"),
+			},
+		}),
+	})
+
+	f("Heading with annotations + link", `# **ULID** Wrapper for *PostgreSQL* and *GORM* [link inside](https://github.com/oklog/ulid)`, nt.Blocks{
+		nt.NewHeading1Block(nt.Heading{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("ULID").AnnotateBold(),
+				*nt.NewTextRichText(" Wrapper for "),
+				*nt.NewTextRichText("PostgreSQL").AnnotateItalic(),
+				*nt.NewTextRichText(" and "),
+				*nt.NewTextRichText("GORM").AnnotateItalic(),
+				*nt.NewTextRichText(" "),
+				*nt.NewLinkRichText("link inside", "https://github.com/oklog/ulid"),
+			},
+		}),
+	})
+
+	f("Paragraph with one link", `Visit [OpenAI](https://openai.com)`, nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Visit "),
+				*nt.NewLinkRichText("OpenAI", "https://openai.com"),
+			},
+			Children: nt.Blocks{},
+		}),
+	})
+
+	f("Paragraph with an Inline link", `Hello https://openai.com`, nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Hello "),
+				*nt.NewLinkRichText("https://openai.com", "https://openai.com"),
+			},
+			Children: nt.Blocks{},
+		}),
+	})
+
+	f("Formatting links", `
+I love supporting the **[EFF](https://eff.org)**.
+This is the *[Markdown Guide](https://www.markdownguide.org)*.
+See the section on [`+"`code`"+`](#code).`,
+		nt.Blocks{
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("I love supporting the "),
+					*nt.NewLinkRichText("EFF", "https://eff.org").AnnotateBold(),
+					*nt.NewTextRichText("."),
+					*nt.NewTextRichText("This is the "),
+					*nt.NewLinkRichText("Markdown Guide", "https://www.markdownguide.org").AnnotateItalic(),
+					*nt.NewTextRichText("."),
+					*nt.NewTextRichText("See the section on "),
+					// Notion rejects relative links, so they are kept as plain text
+					*nt.NewTextRichText("code").AnnotateCode(),
+					*nt.NewTextRichText("."),
+				},
+				Children: nt.Blocks{},
+			}),
+		})
+
+	// --------------
+	// --- LISTS ----
+	// --------------
+
+	f("Simple Bulleted List", `- Item 1
+- Item 2
+- Item 3`,
+		nt.Blocks{
+			nt.NewBulletedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 1"),
+				},
+				Children: nt.Blocks{},
+			}),
+			nt.NewBulletedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 2"),
+				},
+				Children: nt.Blocks{},
+			}),
+			nt.NewBulletedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 3"),
+				},
+				Children: nt.Blocks{},
+			}),
+		})
+
+	f("Simple Numbered List", `1. Item 1
+2. Item 2
+3. Item 3`,
+		nt.Blocks{
+			nt.NewNumberedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 1"),
+				},
+				Children: nt.Blocks{},
+			}),
+			nt.NewNumberedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 2"),
+				},
+				Children: nt.Blocks{},
+			}),
+			nt.NewNumberedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 3"),
+				},
+				Children: nt.Blocks{},
+			}),
+		})
+
+	f("Simple Numbered List followed by a paragraph", `Hello my list:
+1. Item 1
+2. Item 2`,
+		nt.Blocks{ // it's paragraph + list but not nested list
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Hello my"),
+					*nt.NewTextRichText(" list:"),
+				},
+				Children: nt.Blocks{},
+			}),
+			nt.NewNumberedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 1"),
+				},
+				Children: nt.Blocks{},
+			}),
+			nt.NewNumberedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 2"),
+				},
+				Children: nt.Blocks{},
+			}),
+		})
+
+	f("Nested Bulleted List", `- Item 1
+  - Subitem 1.1
+  - Subitem 1.2
+  - Subitem 1.3
+- Item 2
+  - Subitem 2.1
+  - Subitem 2.2
+  - Subitem 2.3`,
+		nt.Blocks{
+			nt.NewBulletedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 1"),
+				},
+				Children: nt.Blocks{
+					nt.NewBulletedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Subitem"),
+							*nt.NewTextRichText(" 1.1"),
+						},
+						Children: nt.Blocks{},
+					}),
+					nt.NewBulletedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Subitem"),
+							*nt.NewTextRichText(" 1.2"),
+						},
+						Children: nt.Blocks{},
+					}),
+					nt.NewBulletedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Subitem"),
+							*nt.NewTextRichText(" 1.3"),
+						},
+						Children: nt.Blocks{},
+					}),
+				},
+			}),
+			nt.NewBulletedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 2"),
+				},
+				Children: nt.Blocks{
+					nt.NewBulletedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Subitem"),
+							*nt.NewTextRichText(" 2.1"),
+						},
+						Children: nt.Blocks{},
+					}),
+					nt.NewBulletedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Subitem"),
+							*nt.NewTextRichText(" 2.2"),
+						},
+						Children: nt.Blocks{},
+					}),
+					nt.NewBulletedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Subitem"),
+							*nt.NewTextRichText(" 2.3"),
+						},
+						Children: nt.Blocks{},
+					}),
+				},
+			}),
+		})
+
+	f("Nested Numbered List Inside Bulleted List", `- Item 1
+  1. Subitem 1.1
+  2. Subitem 1.2
+  3. Subitem 1.3
+- Item 2
+  1. Subitem 2.1
+  2. Subitem 2.2
+  3. Subitem 2.3`,
+		nt.Blocks{
+			nt.NewBulletedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 1"),
+				},
+				Children: nt.Blocks{
+					nt.NewNumberedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Subitem"),
+							*nt.NewTextRichText(" 1.1"),
+						},
+						Children: nt.Blocks{},
+					}),
+					nt.NewNumberedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Subitem"),
+							*nt.NewTextRichText(" 1.2"),
+						},
+						Children: nt.Blocks{},
+					}),
+					nt.NewNumberedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Subitem"),
+							*nt.NewTextRichText(" 1.3"),
+						},
+						Children: nt.Blocks{},
+					}),
+				},
+			}),
+			nt.NewBulletedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 2"),
+				},
+				Children: nt.Blocks{
+					nt.NewNumberedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Subitem"),
+							*nt.NewTextRichText(" 2.1"),
+						},
+						Children: nt.Blocks{},
+					}),
+					nt.NewNumberedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Subitem"),
+							*nt.NewTextRichText(" 2.2"),
+						},
+						Children: nt.Blocks{},
+					}),
+					nt.NewNumberedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Subitem"),
+							*nt.NewTextRichText(" 2.3"),
+						},
+						Children: nt.Blocks{},
+					}),
+				},
+			}),
+		})
+
+	f("3-Level Deep Nested List with Mixed Bulleted and Numbered Items",
+
+		`- **Top Level 1**
+  1. Second Level 1.1 with [a link](https://example.com)
+  2. *Second Level 1.2*
+      - Third Level 1.2.1
+      - Third Level 1.2.2
+- Top Level 2
+  1. Second Level 2.1
+      - Third Level 2.1.1
+      - **Third Level 2.1.2** with *italic text*
+  2. Second Level 2.2`,
+		nt.Blocks{
+			nt.NewBulletedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Top Level 1").AnnotateBold(),
+				},
+				Children: nt.Blocks{
+					nt.NewNumberedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Second Level 1.1 with "),
+							*nt.NewLinkRichText("a link", "https://example.com"),
+						},
+						Children: nt.Blocks{},
+					}),
+					nt.NewNumberedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Second Level 1.2").AnnotateItalic(),
+						},
+						Children: nt.Blocks{
+							nt.NewBulletedListItemBlock(nt.ListItem{
+								RichText: []nt.RichText{
+									*nt.NewTextRichText("Third Level"),
+									*nt.NewTextRichText(" 1.2.1"),
+								},
+								Children: nt.Blocks{},
+							}),
+							nt.NewBulletedListItemBlock(nt.ListItem{
+								RichText: []nt.RichText{
+									*nt.NewTextRichText("Third Level"),
+									*nt.NewTextRichText(" 1.2.2"),
+								},
+								Children: nt.Blocks{},
+							}),
+						},
+					}),
+				},
+			}),
+
+			nt.NewBulletedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Top Level"),
+					*nt.NewTextRichText(" 2"),
+				},
+				Children: nt.Blocks{
+					nt.NewNumberedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Second Level"),
+							*nt.NewTextRichText(" 2.1"),
+						},
+						Children: nt.Blocks{
+							nt.NewBulletedListItemBlock(nt.ListItem{
+								RichText: []nt.RichText{
+									*nt.NewTextRichText("Third Level"),
+									*nt.NewTextRichText(" 2.1.1"),
+								},
+								Children: nt.Blocks{},
+							}),
+							nt.NewBulletedListItemBlock(nt.ListItem{
+								RichText: []nt.RichText{
+									*nt.NewTextRichText("Third Level 2.1.2").AnnotateBold(),
+									*nt.NewTextRichText(" with "),
+									*nt.NewTextRichText("italic text").AnnotateItalic(),
+								},
+								Children: nt.Blocks{},
+							}),
+						},
+					}),
+					nt.NewNumberedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Second Level"),
+							*nt.NewTextRichText(" 2.2"),
+						},
+						Children: nt.Blocks{},
+					}),
+				},
+			}),
+		})
+
+	f("Unordered List Items With Numbers", `
+- 1968\. A great year!
+- I think 1969 was second best.`,
+		nt.Blocks{
+			nt.NewBulletedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("1968. A great year"),
+					*nt.NewTextRichText("!"),
+				},
+				Children: nt.Blocks{},
+			}),
+			nt.NewBulletedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("I think 1969 was second"),
+					*nt.NewTextRichText(" best."),
+				},
+				Children: nt.Blocks{},
+			}),
+		})
+
+	// --------------
+	// --- TASKS ----
+	// --------------
+
+	f("Simple TODO List", `- [ ] Item 1
+- [ ] Item 2
+- [x] Item 3`,
+		nt.Blocks{
+			nt.NewToDoBlock(nt.ToDo{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 1"),
+				},
+			}),
+			nt.NewToDoBlock(nt.ToDo{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 2"),
+				},
+			}),
+			nt.NewToDoBlock(nt.ToDo{
+				Checked: true,
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 3"),
+				},
+			}),
+		})
+
+	f("Simple TODO List + emphasis", `- [ ] Item 1
+- [ ] _Item 2_
+- [x] **Item 3**`,
+		nt.Blocks{
+			nt.NewToDoBlock(nt.ToDo{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 1"),
+				},
+			}),
+			nt.NewToDoBlock(nt.ToDo{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item 2").AnnotateItalic(),
+				},
+			}),
+			nt.NewToDoBlock(nt.ToDo{
+				Checked: true,
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item 3").AnnotateBold(),
+				},
+			}),
+		})
+
+	f("Bulleted List with Nested TODO List", `
+- Item 1
+	- [ ] TODO 1
+	- [x] TODO 2
+- Item 2`,
+		nt.Blocks{
+			nt.NewBulletedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 1"),
+				},
+				Children: nt.Blocks{
+					nt.NewToDoBlock(nt.ToDo{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("TODO"),
+							*nt.NewTextRichText(" 1"),
+						},
+					}),
+					nt.NewToDoBlock(nt.ToDo{
+						Checked: true,
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("TODO"),
+							*nt.NewTextRichText(" 2"),
+						},
+					}),
+				},
+			}),
+			nt.NewBulletedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Item"),
+					*nt.NewTextRichText(" 2"),
+				},
+				Children: nt.Blocks{},
+			}),
+		})
+
+	// --------------
+	// --- CODE -----
+	// --------------
+
+	f("Simple Non-Fenced Code Block", `
+	package main
+	func main() {
+		fmt.Println("Hello, World!")
+	}`,
+		nt.Blocks{
+			nt.NewCodeBlock(nt.Code{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("package main\nfunc main() {\n\tfmt.Println(\"Hello, World!\")\n}"),
+				},
+				Language: "plain text", // Notion requires a language, so the default one is set
+			}),
+		})
+
+	f("Simple Fenced Code Block", "```go\npackage main\n\nfunc main() {\n\tfmt.Println(\"Hello, World!\")\n}\n```",
+		nt.Blocks{
+			nt.NewCodeBlock(nt.Code{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("package main\n\nfunc main() {\n\tfmt.Println(\"Hello, World!\")\n}"),
+				},
+				Language: "go",
+			}),
+		})
+
+	f("Heading H1 + Heading H2 + Fenced Code Block", fmt.Sprintf(`# Heading 1
+## Heading 2
+
+%sgo
+package main
+
+func main() {
+		fmt.Println("Hello, World!")
+}
+%s`, "```", "```"),
+		nt.Blocks{
+			nt.NewHeading1Block(nt.Heading{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Heading"),
+					*nt.NewTextRichText(" 1"),
+				},
+			}),
+			nt.NewHeading2Block(nt.Heading{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Heading"),
+					*nt.NewTextRichText(" 2"),
+				},
+			}),
+			nt.NewCodeBlock(nt.Code{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("package main\n\nfunc main() {\n\t\tfmt.Println(\"Hello, World!\")\n}"),
+				},
+				Language: "go",
+			}),
+		})
+
+	// --------------
+	// --- TABLES ---
+	// --------------
+
+	f("Simple Markdown Table", `| Column1 | Column2 |
+|---------|---------|
+| Value1  | Value2  |
+| Value3  | Value4  |`,
+		nt.Blocks{
+			nt.NewTableBlock(nt.Table{
+				TableWidth:      2,
+				HasColumnHeader: true,
+				Children: nt.Blocks{
+					nt.NewTableRowBlock(nt.TableRow{
+						Cells: [][]nt.RichText{
+							{*nt.NewTextRichText("Column1")},
+							{*nt.NewTextRichText("Column2")},
+						},
+					}),
+					nt.NewTableRowBlock(nt.TableRow{
+						Cells: [][]nt.RichText{
+							{*nt.NewTextRichText("Value1")},
+							{*nt.NewTextRichText("Value2")},
+						},
+					}),
+					nt.NewTableRowBlock(nt.TableRow{
+						Cells: [][]nt.RichText{
+							{*nt.NewTextRichText("Value3")},
+							{*nt.NewTextRichText("Value4")},
+						},
+					}),
+				},
+			}),
+		})
+
+	f("Markdown Table with Emphasis in Cells", `| Column1    | Column2    |
+|------------|------------|
+| *Italic*   | **Bold**   |
+| ~~Strike~~ | `+"`Code`"+` |`,
+		nt.Blocks{
+			nt.NewTableBlock(nt.Table{
+				TableWidth:      2,
+				HasColumnHeader: true,
+				Children: nt.Blocks{
+					nt.NewTableRowBlock(nt.TableRow{
+						Cells: [][]nt.RichText{
+							{*nt.NewTextRichText("Column1")},
+							{*nt.NewTextRichText("Column2")},
+						},
+					}),
+					nt.NewTableRowBlock(nt.TableRow{
+						Cells: [][]nt.RichText{
+							{*nt.NewTextRichText("Italic").AnnotateItalic()},
+							{*nt.NewTextRichText("Bold").AnnotateBold()},
+						},
+					}),
+					nt.NewTableRowBlock(nt.TableRow{
+						Cells: [][]nt.RichText{
+							{*nt.NewTextRichText("Strike").AnnotateStrikethrough()},
+							{*nt.NewTextRichText("Code").AnnotateCode()},
+						},
+					}),
+				},
+			}),
+		})
+
+	f("Markdown Table with URLs in Cells", `| Column1       | Column2        |
+|---------------|----------------|
+| [Google](https://google.com) | [OpenAI](https://openai.com) |
+| Plain URL     | https://example.com |`,
+		nt.Blocks{
+			nt.NewTableBlock(nt.Table{
+				TableWidth:      2,
+				HasColumnHeader: true,
+				Children: nt.Blocks{
+					nt.NewTableRowBlock(nt.TableRow{
+						Cells: [][]nt.RichText{
+							{*nt.NewTextRichText("Column1")},
+							{*nt.NewTextRichText("Column2")},
+						},
+					}),
+					nt.NewTableRowBlock(nt.TableRow{
+						Cells: [][]nt.RichText{
+							{*nt.NewLinkRichText("Google", "https://google.com")},
+							{*nt.NewLinkRichText("OpenAI", "https://openai.com")},
+						},
+					}),
+					nt.NewTableRowBlock(nt.TableRow{
+						Cells: [][]nt.RichText{
+							{*nt.NewTextRichText("Plain"), *nt.NewTextRichText(" URL")},
+							{*nt.NewLinkRichText("https://example.com", "https://example.com")},
+						},
+					}),
+				},
+			}),
+		})
+
+	// -------------------
+	// --- BLOCKQUOTES ---
+	// -------------------
+
+	f("Simple Blockquote", `> This is a block quote`,
+		nt.Blocks{
+			nt.NewQuoteBlock(nt.Quote{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("This is a block"),
+					*nt.NewTextRichText(" quote"),
+				},
+				Children: nt.Blocks{},
+			}),
+		})
+
+	f("Multiline Blockquote", "> This is a block quote\n>\n> This is the last line",
+		nt.Blocks{
+			nt.NewQuoteBlock(nt.Quote{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("This is a block"),
+					*nt.NewTextRichText(" quote"),
+					*nt.NewTextRichText("This is the last"),
+					*nt.NewTextRichText(" line"),
+				},
+				Children: nt.Blocks{},
+			}),
+		})
+
+	f("Nested Blockquotes", `> This is a blockquote
+> > This is a nested blockquote`,
+		nt.Blocks{
+			nt.NewQuoteBlock(nt.Quote{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("This is a"),
+					*nt.NewTextRichText(" blockquote"),
+				},
+				Children: nt.Blocks{
+					nt.NewQuoteBlock(nt.Quote{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("This is a nested"),
+							*nt.NewTextRichText(" blockquote"),
+						},
+						Children: nt.Blocks{},
+					}),
+				},
+			}),
+		})
+
+	f("Blockquotes with nested elements", `
+> #### The quarterly results look great!
+> Second line
+`,
+		nt.Blocks{
+			nt.NewQuoteBlock(nt.Quote{
+				RichText: []nt.RichText{},
+				Children: nt.Blocks{
+					nt.NewHeading3Block(nt.Heading{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("The quarterly results look great"),
+							*nt.NewTextRichText("!"),
+						},
+					}),
+					nt.NewParagraphBlock(nt.Paragraph{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Second"),
+							*nt.NewTextRichText(" line"),
+						},
+						Children: nt.Blocks{},
+					}),
+				},
+			}),
+		},
+	)
+
+	f("Blockquotes with more nested elements", `
+> #### The quarterly results look great!
+>
+> - Revenue was off the chart.
+> - Profits were higher than ever.
+>
+>  *Everything* is going according to **plan**.
+`,
+		nt.Blocks{
+			nt.NewQuoteBlock(nt.Quote{
+				RichText: []nt.RichText{},
+				Children: nt.Blocks{
+					nt.NewHeading3Block(nt.Heading{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("The quarterly results look great"),
+							*nt.NewTextRichText("!"),
+						},
+					}),
+
+					nt.NewBulletedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Revenue was off the"),
+							*nt.NewTextRichText(" chart."),
+						},
+						Children: nt.Blocks{},
+					}),
+					nt.NewBulletedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Profits were higher than"),
+							*nt.NewTextRichText(" ever."),
+						},
+						Children: nt.Blocks{},
+					}),
+
+					nt.NewParagraphBlock(nt.Paragraph{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Everything").AnnotateItalic(),
+							*nt.NewTextRichText(" is going according to "),
+							*nt.NewTextRichText("plan").AnnotateBold(),
+							*nt.NewTextRichText("."),
+						},
+						Children: nt.Blocks{},
+					}),
+				},
+			}),
+		},
+	)
+
+	f("GitHub alert", "> [!NOTE]\n> Useful *information*.",
+		nt.Blocks{
+			nt.NewCalloutBlock(nt.Callout{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Useful "),
+					*nt.NewTextRichText("information").AnnotateItalic(),
+					*nt.NewTextRichText("."),
+				},
+				Icon:     &nt.Icon{Type: "emoji", Emoji: func() *nt.Emoji { e := nt.Emoji("ℹ️"); return &e }()},
+				Children: nt.Blocks{},
+				Color:    "blue_background",
+			}),
+		})
+
+	f("GitHub alert with nested blocks", "> [!warning]\n>\n> - Item",
+		nt.Blocks{
+			nt.NewCalloutBlock(nt.Callout{
+				RichText: []nt.RichText{},
+				Icon:     &nt.Icon{Type: "emoji", Emoji: func() *nt.Emoji { e := nt.Emoji("⚠️"); return &e }()},
+				Children: nt.Blocks{
+					nt.NewBulletedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Item"),
+						},
+						Children: nt.Blocks{},
+					}),
+				},
+				Color: "yellow_background",
+			}),
+		})
+
+	f("Unknown GitHub alert is kept as a quote", "> [!UNKNOWN]\n> Text",
+		nt.Blocks{
+			nt.NewQuoteBlock(nt.Quote{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("["),
+					*nt.NewTextRichText("!UNKNOWN"),
+					*nt.NewTextRichText("]"),
+					*nt.NewTextRichText("Text"),
+				},
+				Children: nt.Blocks{},
+			}),
+		})
+
+	// --------------
+	// --- IMAGES ---
+	// --------------
+
+	f("Image Without Caption", `![](https://example.com/image.png)`,
+		nt.Blocks{
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{},
+				Children: nt.Blocks{
+					nt.NewImageBlock(nt.Image{
+						Type: "external",
+						External: &nt.FileObject{
+							URL: "https://example.com/image.png",
+						},
+						Caption: []nt.RichText{}, // No caption
+					}),
+				},
+			}),
+		})
+
+	f("Image With Caption", `![Alt text](https://example.com/image.png)`,
+		nt.Blocks{
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{},
+				Children: nt.Blocks{
+					nt.NewImageBlock(nt.Image{
+						Type: "external",
+						External: &nt.FileObject{
+							URL: "https://example.com/image.png",
+						},
+						Caption: []nt.RichText{
+							*nt.NewTextRichText("Alt text"),
+						},
+					}),
+				},
+			}),
+		})
+
+	f("Image with URL caption", `[![Alt text](https://example.com/image.png)](https://google.com)`,
+		nt.Blocks{
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{},
+				Children: nt.Blocks{
+					nt.NewImageBlock(nt.Image{
+						Type: "external",
+						External: &nt.FileObject{
+							URL: "https://example.com/image.png",
+						},
+						Caption: []nt.RichText{
+							*nt.NewTextRichText("Alt text").MakeLink("https://google.com"),
+						},
+					}),
+				},
+			}),
+		})
+
+	// --------------
+	// --- MISC -----
+	// --------------
+
+	f("Horizontal Rule", `---`, nt.Blocks{
+		nt.NewDividerBlock(),
+	})
+
+	f("hack: markdown comment", `[a hidden comment]: #`, nt.Blocks{})
+
+	f("hack: comment between lines", `
+---
+[a hidden comment]: #
+---`,
+		nt.Blocks{
+			nt.NewDividerBlock(),
+			nt.NewDividerBlock(),
+		})
+
+	// --------------
+	// --- HTML -----
+	// --------------
+
+	f("Basic HTML", `Hello<br>World`, nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Hello"),
+				*nt.NewTextRichText("\n"),
+				*nt.NewTextRichText("World"),
+			},
+			Children: nt.Blocks{},
+		}),
+	})
+
+	f("Inline HTML formatting", `<b>Bold</b>, <STRONG>Strong</STRONG>, <i>it</i> <em>em</em> <s>s</s> <del>del</del> <u>u</u>`,
+		nt.Blocks{
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Bold").AnnotateBold(),
+					*nt.NewTextRichText(", "),
+					*nt.NewTextRichText("Strong").AnnotateBold(),
+					*nt.NewTextRichText(", "),
+					*nt.NewTextRichText("it").AnnotateItalic(),
+					*nt.NewTextRichText(" "),
+					*nt.NewTextRichText("em").AnnotateItalic(),
+					*nt.NewTextRichText(" "),
+					*nt.NewTextRichText("s").AnnotateStrikethrough(),
+					*nt.NewTextRichText(" "),
+					*nt.NewTextRichText("del").AnnotateStrikethrough(),
+					*nt.NewTextRichText(" "),
+					*nt.NewTextRichText("u").AnnotateUnderline(),
+				},
+				Children: nt.Blocks{},
+			}),
+		})
+	f("Inline HTML code, links and marks", `Press <kbd>Ctrl</kbd> <code>C</code>, <a href="https://example.com">see <b>Docs</b></a> <mark>Hi</mark>`,
+		nt.Blocks{
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Press "),
+					*nt.NewTextRichText("Ctrl").AnnotateCode(),
+					*nt.NewTextRichText(" "),
+					*nt.NewTextRichText("C").AnnotateCode(),
+					*nt.NewTextRichText(", "),
+					*nt.NewLinkRichText("see ", "https://example.com"),
+					*nt.NewLinkRichText("Docs", "https://example.com").AnnotateBold(),
+					*nt.NewTextRichText(" "),
+					*nt.NewTextRichText("Hi").AnnotateColor(nt.ColorYellowBackground),
+				},
+				Children: nt.Blocks{},
+			}),
+		})
+	f("Inline HTML superscript and subscript", `E = mc<sup>2</sup>, H<sub>2</sub>O`,
+		nt.Blocks{
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("E = mc"),
+					*nt.NewTextRichText("²"),
+					*nt.NewTextRichText(", H"),
+					*nt.NewTextRichText("₂"),
+					*nt.NewTextRichText("O"),
+				},
+				Children: nt.Blocks{},
+			}),
+		})
+	f("Unknown and unpaired inline HTML tags are stripped", `<span class="x">Keep</span> <b>Case</b></i><!-- comment --> <img src="a.png"/>`,
+		nt.Blocks{
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Keep"),
+					*nt.NewTextRichText(" "),
+					*nt.NewTextRichText("Case").AnnotateBold(),
+					*nt.NewTextRichText(" "),
+				},
+				Children: nt.Blocks{
+					// images are not stripped, they become image blocks
+					nt.NewImageBlock(nt.Image{
+						Type:     nt.FileTypeExternal,
+						External: &nt.FileObject{URL: "a.png"},
+						Caption:  []nt.RichText{},
+					}),
+				},
+			}),
+		})
+
+	f("HTML Block", `<div>
+  <p>This is an <b>HTML</b>
+  block</p>
+</div>`, nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("This is an "),
+				*nt.NewTextRichText("HTML").AnnotateBold(),
+				*nt.NewTextRichText(" block"),
+			},
+			Children: nil,
+		}),
+	})
+	f("HTML Block with centered header image and badges", `<p align="center">
+  <img src="https://example.com/logo.png" alt="Logo" width="200">
+  <br>
+  <b>Peppers</b>
+</p>
+<p align="center">
+  <a href="https://ci.example.com"><img src="https://ci.example.com/badge.svg" alt="CI"></a>
+  <a href="https://pkg.go.dev"><img src="https://pkg.go.dev/badge.svg"></a>
+</p>`, nt.Blocks{
+		nt.NewImageBlock(nt.Image{
+			Type:     nt.FileTypeExternal,
+			External: &nt.FileObject{URL: "https://example.com/logo.png"},
+			Caption:  []nt.RichText{*nt.NewTextRichText("Logo")},
+		}),
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("\n"),
+				*nt.NewTextRichText("Peppers").AnnotateBold(),
+			},
+			Children: nil,
+		}),
+		nt.NewImageBlock(nt.Image{
+			Type:     nt.FileTypeExternal,
+			External: &nt.FileObject{URL: "https://ci.example.com/badge.svg"},
+			Caption:  []nt.RichText{*nt.NewLinkRichText("CI", "https://ci.example.com")},
+		}),
+		nt.NewImageBlock(nt.Image{
+			Type:     nt.FileTypeExternal,
+			External: &nt.FileObject{URL: "https://pkg.go.dev/badge.svg"},
+			Caption:  []nt.RichText{},
+		}),
+	})
+	f("Inline HTML badge row", `<a href="https://ci.example.com"><img src="https://ci.example.com/badge.svg" alt="CI"></a> <a href="https://pkg.go.dev"><img src="https://pkg.go.dev/badge.svg"></a>`, nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{},
+			Children: nt.Blocks{
+				nt.NewImageBlock(nt.Image{
+					Type:     nt.FileTypeExternal,
+					External: &nt.FileObject{URL: "https://ci.example.com/badge.svg"},
+					Caption:  []nt.RichText{*nt.NewLinkRichText("CI", "https://ci.example.com")},
+				}),
+				nt.NewImageBlock(nt.Image{
+					Type:     nt.FileTypeExternal,
+					External: &nt.FileObject{URL: "https://pkg.go.dev/badge.svg"},
+					Caption:  []nt.RichText{},
+				}),
+			},
+		}),
+	})
+	f("Inline HTML image inside text", `See <img src="https://example.com/arch.png" alt="Architecture"> below`, nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{*nt.NewTextRichText("See ")},
+			Children: nt.Blocks{
+				nt.NewImageBlock(nt.Image{
+					Type:     nt.FileTypeExternal,
+					External: &nt.FileObject{URL: "https://example.com/arch.png"},
+					Caption:  []nt.RichText{*nt.NewTextRichText("Architecture")},
+				}),
+				nt.NewParagraphBlock(nt.Paragraph{
+					RichText: []nt.RichText{*nt.NewTextRichText(" below")},
+				}),
+			},
+		}),
+	})
+	f("HTML table with header and lists in cells", `<table>
+  <thead><tr><td>Name</td><td>Features</td></tr></thead>
+  <tbody>
+    <tr>
+      <td><b>pprs</b></td>
+      <td><ol><li>Sync</li><li>Pull</li></ol></td>
+    </tr>
+    <tr><td>other</td></tr>
+  </tbody>
+</table>`, nt.Blocks{
+		nt.NewTableBlock(nt.Table{
+			TableWidth:      2,
+			HasColumnHeader: true,
+			Children: nt.Blocks{
+				nt.NewTableRowBlock(nt.TableRow{
+					Cells: [][]nt.RichText{
+						{*nt.NewTextRichText("Name")},
+						{*nt.NewTextRichText("Features")},
+					},
+				}),
+				nt.NewTableRowBlock(nt.TableRow{
+					Cells: [][]nt.RichText{
+						{*nt.NewTextRichText("pprs").AnnotateBold()},
+						{
+							*nt.NewTextRichText("1. "),
+							*nt.NewTextRichText("Sync"),
+							*nt.NewTextRichText("\n"),
+							*nt.NewTextRichText("2. "),
+							*nt.NewTextRichText("Pull"),
+						},
+					},
+				}),
+				nt.NewTableRowBlock(nt.TableRow{
+					Cells: [][]nt.RichText{
+						{*nt.NewTextRichText("other")},
+						{},
+					},
+				}),
+			},
+		}),
+	})
+	f("HTML table split by blank lines with Markdown in cells", `<table>
+<tr>
+<td>
+
+- Sync
+- **Pull**
+
+</td>
+<td></td>
+</tr>
+</table>`, nt.Blocks{
+		nt.NewTableBlock(nt.Table{
+			TableWidth: 2,
+			Children: nt.Blocks{
+				nt.NewTableRowBlock(nt.TableRow{
+					Cells: [][]nt.RichText{
+						{
+							*nt.NewTextRichText("• "),
+							*nt.NewTextRichText("Sync"),
+							*nt.NewTextRichText("\n"),
+							*nt.NewTextRichText("• "),
+							*nt.NewTextRichText("Pull").AnnotateBold(),
+						},
+						{}, // empty cells are not null
+					},
+				}),
+			},
+		}),
+	})
+	f("HTML table with row headers", `<table>
+<tr><th>A</th><td>1</td></tr>
+<tr><th>B</th><td>2</td></tr>
+</table>`, nt.Blocks{
+		nt.NewTableBlock(nt.Table{
+			TableWidth:   2,
+			HasRowHeader: true,
+			Children: nt.Blocks{
+				nt.NewTableRowBlock(nt.TableRow{
+					Cells: [][]nt.RichText{
+						{*nt.NewTextRichText("A")},
+						{*nt.NewTextRichText("1")},
+					},
+				}),
+				nt.NewTableRowBlock(nt.TableRow{
+					Cells: [][]nt.RichText{
+						{*nt.NewTextRichText("B")},
+						{*nt.NewTextRichText("2")},
+					},
+				}),
+			},
+		}),
+	})
+	f("Unsupported HTML Block is kept as raw HTML", `<section>
+  <p>Raw</p>
+</section>`, nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("<section>\n  <p>Raw</p>\n</section>"),
+			},
+			Children: nil,
+		}),
+	})
+
+	// Markdownlint should be ignored (omitted)
+	f("HTML Block with Markdownlint comment only", `<!-- markdownlint-disable blah blah-->`, nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{},
+			Children: nil,
+		}),
+	})
+	f("HTML Block with Markdownlint comment", `<!-- markdownlint-disable blah blah--><p>Hello</p>`, nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Hello"),
+			},
+			Children: nil,
+		}),
+	})
+
+	f("HTML details with Markdown inside", `<details>
+<summary>Build output</summary>
+
+Some **markdown**
+
+- item
+
+</details>`, nt.Blocks{
+		nt.NewToggleBlock(nt.Toggle{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Build output"),
+			},
+			Children: nt.Blocks{
+				nt.NewParagraphBlock(nt.Paragraph{
+					RichText: []nt.RichText{
+						*nt.NewTextRichText("Some "),
+						*nt.NewTextRichText("markdown").AnnotateBold(),
+					},
+					Children: nt.Blocks{},
+				}),
+				nt.NewBulletedListItemBlock(nt.ListItem{
+					RichText: []nt.RichText{
+						*nt.NewTextRichText("item"),
+					},
+					Children: nt.Blocks{},
+				}),
+			},
+		}),
+	})
+	f("HTML details in a single HTML block", `<details><summary>Outer</summary>
+<details><summary>Inner &amp; more</summary>Nested</details>
+</details>`, nt.Blocks{
+		nt.NewToggleBlock(nt.Toggle{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Outer"),
+			},
+			Children: nt.Blocks{
+				nt.NewToggleBlock(nt.Toggle{
+					RichText: []nt.RichText{
+						*nt.NewTextRichText("Inner & more"),
+					},
+					Children: nt.Blocks{
+						nt.NewParagraphBlock(nt.Paragraph{
+							RichText: []nt.RichText{
+								*nt.NewTextRichText("Nested"),
+							},
+							Children: nt.Blocks{},
+						}),
+					},
+				}),
+			},
+		}),
+	})
+	f("HTML details without summary", "<details>\n\nHidden\n\n</details>", nt.Blocks{
+		nt.NewToggleBlock(nt.Toggle{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Details"),
+			},
+			Children: nt.Blocks{
+				nt.NewParagraphBlock(nt.Paragraph{
+					RichText: []nt.RichText{
+						*nt.NewTextRichText("Hidden"),
+					},
+					Children: nt.Blocks{},
+				}),
+			},
+		}),
+	})
+	f("Consecutive HTML details", `<details>
+<summary>One</summary>
+
+First
+
+</details>
+<details>
+<summary>Two</summary>
+
+Second
+
+</details>`, nt.Blocks{
+		nt.NewToggleBlock(nt.Toggle{
+			RichText: []nt.RichText{*nt.NewTextRichText("One")},
+			Children: nt.Blocks{
+				nt.NewParagraphBlock(nt.Paragraph{
+					RichText: []nt.RichText{*nt.NewTextRichText("First")},
+					Children: nt.Blocks{},
+				}),
+			},
+		}),
+		nt.NewToggleBlock(nt.Toggle{
+			RichText: []nt.RichText{*nt.NewTextRichText("Two")},
+			Children: nt.Blocks{
+				nt.NewParagraphBlock(nt.Paragraph{
+					RichText: []nt.RichText{*nt.NewTextRichText("Second")},
+					Children: nt.Blocks{},
+				}),
+			},
+		}),
+	})
+
+	// -----------------
+	// --- FOOTNOTES ---
+	// -----------------
+
+	f("Footnotes rendered as a section", `Hello[^1] world[^note].
+
+[^1]: First note.
+[^note]: Second **note**.
+
+    More text`,
+		nt.Blocks{
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Hello"),
+					*nt.NewTextRichText("¹"),
+					*nt.NewTextRichText(" world"),
+					*nt.NewTextRichText("²"),
+					*nt.NewTextRichText("."),
+				},
+				Children: nt.Blocks{},
+			}),
+			nt.NewDividerBlock(),
+			nt.NewHeading2Block(nt.Heading{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Footnotes"),
+				},
+			}),
+			nt.NewNumberedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("First"),
+					*nt.NewTextRichText(" note."),
+				},
+				Children: nt.Blocks{},
+			}),
+			nt.NewNumberedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Second "),
+					*nt.NewTextRichText("note").AnnotateBold(),
+					*nt.NewTextRichText("."),
+				},
+				Children: nt.Blocks{
+					nt.NewParagraphBlock(nt.Paragraph{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("More"),
+							*nt.NewTextRichText(" text"),
+						},
+						Children: nt.Blocks{},
+					}),
+				},
+			}),
+		})
+
+	f("Footnote reference with two-digit index", func() string {
+		var source strings.Builder
+		for i := 1; i <= 12; i++ {
+			source.WriteString(fmt.Sprintf("[^%d]", i))
+		}
+		source.WriteString("\n\n")
+		for i := 1; i <= 12; i++ {
+			source.WriteString(fmt.Sprintf("[^%d]: Note\n", i))
+		}
+		return source.String()
+	}(), func() nt.Blocks {
+		refs := make([]nt.RichText, 0)
+		notes := make(nt.Blocks, 0)
+		for _, marker := range []string{"¹", "²", "³", "⁴", "⁵", "⁶", "⁷", "⁸", "⁹", "¹⁰", "¹¹", "¹²"} {
+			refs = append(refs, *nt.NewTextRichText(marker))
+			notes = append(notes, nt.NewNumberedListItemBlock(nt.ListItem{
+				RichText: []nt.RichText{*nt.NewTextRichText("Note")},
+				Children: nt.Blocks{},
+			}))
+		}
+		return append(nt.Blocks{
+			nt.NewParagraphBlock(nt.Paragraph{RichText: refs, Children: nt.Blocks{}}),
+			nt.NewDividerBlock(),
+			nt.NewHeading2Block(nt.Heading{
+				RichText: []nt.RichText{*nt.NewTextRichText("Footnotes")},
+			}),
+		}, notes...)
+	}())
+
+	// ------------------------
+	// --- DEFINITION LISTS ---
+	// ------------------------
+
+	f("Definition list", `Apple
+:   Pomaceous *fruit*.
+
+Orange
+:   The fruit.
+:   A color.`,
+		nt.Blocks{
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Apple").AnnotateBold(),
+				},
+				Children: nt.Blocks{
+					nt.NewParagraphBlock(nt.Paragraph{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Pomaceous "),
+							*nt.NewTextRichText("fruit").AnnotateItalic(),
+							*nt.NewTextRichText("."),
+						},
+					}),
+				},
+			}),
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Orange").AnnotateBold(),
+				},
+				Children: nt.Blocks{
+					nt.NewParagraphBlock(nt.Paragraph{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("The"),
+							*nt.NewTextRichText(" fruit."),
+						},
+					}),
+					nt.NewParagraphBlock(nt.Paragraph{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("A"),
+							*nt.NewTextRichText(" color."),
+						},
+					}),
+				},
+			}),
+		})
+
+	f("Definition list with loose description", `Term
+
+:   Loose description
+
+    - with list`,
+		nt.Blocks{
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText("Term").AnnotateBold(),
+				},
+				Children: nt.Blocks{
+					nt.NewParagraphBlock(nt.Paragraph{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("Loose"),
+							*nt.NewTextRichText(" description"),
+						},
+						Children: nt.Blocks{},
+					}),
+					nt.NewBulletedListItemBlock(nt.ListItem{
+						RichText: []nt.RichText{
+							*nt.NewTextRichText("with"),
+							*nt.NewTextRichText(" list"),
+						},
+						Children: nt.Blocks{},
+					}),
+				},
+			}),
+		})
+
+	// --------------------
+	// --- FRONT MATTER ---
+	// --------------------
+
+	f("YAML front matter is not leaked into the body", "---\ntitle: Hello\n---\nBody", nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Body"),
+			},
+			Children: nt.Blocks{},
+		}),
+	})
+	f("TOML front matter is not leaked into the body", "+++\ntitle = \"Hello\"\n+++\nBody", nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Body"),
+			},
+			Children: nt.Blocks{},
+		}),
+	})
+	f("Thematic break not at the top is not a front matter", "Body\n\n---\ntitle: Hello\n---", nt.Blocks{
+		nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Body"),
+			},
+			Children: nt.Blocks{},
+		}),
+		nt.NewDividerBlock(),
+		nt.NewHeading2Block(nt.Heading{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("title:"),
+				*nt.NewTextRichText(" Hello"),
+			},
+		}),
+	})
+
+	// --------------
+	// --- LIMITS ---
+	// --------------
+
+	f("Long code block is split into several rich texts",
+		"```go\n"+strings.Repeat("x", 4500)+"\n```",
+		nt.Blocks{
+			nt.NewCodeBlock(nt.Code{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText(strings.Repeat("x", 2000)),
+					*nt.NewTextRichText(strings.Repeat("x", 2000)),
+					*nt.NewTextRichText(strings.Repeat("x", 500)),
+				},
+				Language: "go",
+			}),
+		})
+
+	f("Long bold link is split keeping annotations and link",
+		"**["+strings.Repeat("y", 2500)+"](https://example.com)**",
+		nt.Blocks{
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{
+					*nt.NewLinkRichText(strings.Repeat("y", 2000), "https://example.com").AnnotateBold(),
+					*nt.NewLinkRichText(strings.Repeat("y", 500), "https://example.com").AnnotateBold(),
+				},
+				Children: nt.Blocks{},
+			}),
+		})
+
+	f("Long HTML paragraph is split",
+		"<p>"+strings.Repeat("p", 2500)+"</p>",
+		nt.Blocks{
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText(strings.Repeat("p", 2000)),
+					*nt.NewTextRichText(strings.Repeat("p", 500)),
+				},
+			}),
+		})
+
+	f("Long HTML table cell is split",
+		"<table><tr><td>"+strings.Repeat("c", 2500)+"</td></tr></table>",
+		nt.Blocks{
+			nt.NewTableBlock(nt.Table{
+				TableWidth: 1,
+				Children: nt.Blocks{
+					nt.NewTableRowBlock(nt.TableRow{
+						Cells: [][]nt.RichText{{
+							*nt.NewTextRichText(strings.Repeat("c", 2000)),
+							*nt.NewTextRichText(strings.Repeat("c", 500)),
+						}},
+					}),
+				},
+			}),
+		})
+
+	f("Long text is split by UTF-16 length",
+		"```\n"+strings.Repeat("😀", 1001)+"\n```",
+		nt.Blocks{
+			nt.NewCodeBlock(nt.Code{
+				RichText: []nt.RichText{
+					*nt.NewTextRichText(strings.Repeat("😀", 1000)),
+					*nt.NewTextRichText("😀"),
+				},
+				Language: "plain text",
+			}),
+		})
+
+	run()
+}
+
+func TestParser_ParseBlocks_FootnoteStyles(t *testing.T) {
+	const source = `Hello[^1]
+
+[^1]: The note.
+
+    More text`
+
+	reference := nt.NewParagraphBlock(nt.Paragraph{
+		RichText: []nt.RichText{
+			*nt.NewTextRichText("Hello"),
+			*nt.NewTextRichText("¹"),
+		},
+		Children: nt.Blocks{},
+	})
+	moreText := nt.NewParagraphBlock(nt.Paragraph{
+		RichText: []nt.RichText{
+			*nt.NewTextRichText("More"),
+			*nt.NewTextRichText(" text"),
+		},
+		Children: nt.Blocks{},
+	})
+	emoji := nt.Emoji("📝")
+
+	tests := []struct {
+		style    jalapeno.FootnoteStyle
+		expected nt.Blocks
+	}{
+		{
+			style: jalapeno.FootnoteStyleCallout,
+			expected: nt.Blocks{
+				reference,
+				nt.NewCalloutBlock(nt.Callout{
+					RichText: []nt.RichText{
+						*nt.NewTextRichText("¹ "),
+						*nt.NewTextRichText("The"),
+						*nt.NewTextRichText(" note."),
+					},
+					Icon:     &nt.Icon{Type: "emoji", Emoji: &emoji},
+					Children: nt.Blocks{moreText},
+					Color:    "gray_background",
+				}),
+			},
+		},
+		{
+			style: jalapeno.FootnoteStyleToggle,
+			expected: nt.Blocks{
+				reference,
+				nt.NewToggleBlock(nt.Toggle{
+					RichText: []nt.RichText{
+						*nt.NewTextRichText("Footnote 1"),
+					},
+					Children: nt.Blocks{
+						nt.NewParagraphBlock(nt.Paragraph{
+							RichText: []nt.RichText{
+								*nt.NewTextRichText("The"),
+								*nt.NewTextRichText(" note."),
+							},
+						}),
+						moreText,
+					},
+				}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			p := jalapeno.NewParser(goldmark.New(
+				goldmark.WithExtensions(extension.GFM, extension.Footnote),
+			), jalapeno.WithFootnoteStyle(tt.style))
+
+			blocks, _, err := p.ParseBlocks([]byte(source))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, blocks)
+		})
+	}
+}
+
+func TestParser_ParseBlocks_DefinitionListToggles(t *testing.T) {
+	p := jalapeno.NewParser(goldmark.New(
+		goldmark.WithExtensions(extension.DefinitionList),
+	), jalapeno.WithDefinitionListStyle(jalapeno.DefinitionListStyleToggle))
+
+	blocks, _, err := p.ParseBlocks([]byte("Apple\n:   Pomaceous fruit.\n"))
+	require.NoError(t, err)
+	assert.Equal(t, nt.Blocks{
+		nt.NewToggleBlock(nt.Toggle{
+			RichText: []nt.RichText{
+				*nt.NewTextRichText("Apple"),
+			},
+			Children: nt.Blocks{
+				nt.NewParagraphBlock(nt.Paragraph{
+					RichText: []nt.RichText{
+						*nt.NewTextRichText("Pomaceous fruit."),
+					},
+				}),
+			},
+		}),
+	}, blocks)
+}
+
+func TestParser_Convert_FrontMatter(t *testing.T) {
+	date := nt.Date(time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC))
+	expectedProps := nt.Properties{
+		"title":     nt.TitleProperty{Title: []nt.RichText{*nt.NewTextRichText("Hello")}},
+		"tags":      nt.MultiSelectProperty{MultiSelect: []nt.Option{{Name: "go"}, {Name: "notion"}}},
+		"published": nt.CheckboxProperty{Checkbox: true},
+		"rating":    nt.NumberProperty{Number: 4.5},
+		"date":      nt.DateProperty{Date: &nt.DateObject{Start: &date}},
+		"source":    nt.URLProperty{URL: "https://example.com"},
+		"author":    nt.RichTextProperty{RichText: []nt.RichText{*nt.NewTextRichText("Jane")}},
+	}
+
+	sources := map[string]string{
+		"yaml": `---
+title: Hello
+tags: [go, notion]
+published: true
+rating: 4.5
+date: 2024-12-21
+source: https://example.com
+author: Jane
+icon: 🌶️
+cover: https://example.com/cover.png
+---
+# Heading`,
+		"toml": `+++
+title = "Hello"
+tags = "go, notion"
+published = true
+rating = 4.5
+date = 2024-12-21
+source = "https://example.com"
+author = "Jane"
+icon = "🌶️"
+cover = "https://example.com/cover.png"
++++
+# Heading`,
+	}
+
+	for name, source := range sources {
+		t.Run(name, func(t *testing.T) {
+			result, err := parserInstance.Convert(context.Background(), []byte(source))
+			require.NoError(t, err)
+			frontMatter, blocks := result.FrontMatter, result.Blocks
+
+			assert.Equal(t, expectedProps, frontMatter.Properties())
+
+			emoji := nt.Emoji("🌶️")
+			assert.Equal(t, &nt.Icon{Type: "emoji", Emoji: &emoji}, frontMatter.Icon())
+			assert.Equal(t, &nt.Image{
+				Type:     nt.FileTypeExternal,
+				External: &nt.FileObject{URL: "https://example.com/cover.png"},
+			}, frontMatter.Cover())
+
+			require.Len(t, blocks, 1)
+			assert.Equal(t, nt.BlockTypeHeading1, blocks[0].GetType())
+		})
+	}
+
+	t.Run("no front matter", func(t *testing.T) {
+		result, err := parserInstance.Convert(context.Background(), []byte("# Heading"))
+		require.NoError(t, err)
+		assert.Nil(t, result.FrontMatter.Title())
+		assert.Nil(t, result.FrontMatter.Icon())
+		assert.Nil(t, result.FrontMatter.Cover())
+	})
+
+	t.Run("page ID", func(t *testing.T) {
+		result, err := parserInstance.Convert(context.Background(), []byte("---\nnotion_page_id: abc123\nrepo: peppers\n---\nBody"))
+		require.NoError(t, err)
+		assert.Equal(t, "abc123", result.FrontMatter.PageID())
+		assert.Equal(t, nt.Properties{
+			"repo": nt.RichTextProperty{RichText: []nt.RichText{*nt.NewTextRichText("peppers")}},
+		}, result.FrontMatter.Properties(), "page ID is not a property")
+	})
+
+	t.Run("malformed front matter", func(t *testing.T) {
+		_, err := parserInstance.Convert(context.Background(), []byte("---\ntitle: [oops\n---\nBody"))
+		require.Error(t, err)
+	})
+}
+
+func TestParser_ParseBlocks_HeadingStrategies(t *testing.T) {
+	const source = "# One\n## Two\n### Three\n#### Four\n###### Six"
+
+	heading := func(level int, text string) nt.Block {
+		return nt.NewHeadingBlock(nt.Heading{RichText: []nt.RichText{*nt.NewTextRichText(text)}}, level)
+	}
+
+	tests := []struct {
+		strategy jalapeno.HeadingStrategy
+		expected nt.Blocks
+	}{
+		{
+			strategy: jalapeno.HeadingStrategyClamp,
+			expected: nt.Blocks{
+				heading(1, "One"), heading(2, "Two"), heading(3, "Three"), heading(3, "Four"), heading(3, "Six"),
+			},
+		},
+		{
+			strategy: jalapeno.HeadingStrategyShift,
+			expected: nt.Blocks{
+				heading(1, "One"), heading(1, "Two"), heading(2, "Three"), heading(3, "Four"), heading(3, "Six"),
+			},
+		},
+		{
+			strategy: jalapeno.HeadingStrategyParagraph,
+			expected: nt.Blocks{
+				heading(1, "One"), heading(2, "Two"), heading(3, "Three"),
+				nt.NewParagraphBlock(nt.Paragraph{
+					RichText: []nt.RichText{*nt.NewTextRichText("Four").AnnotateBold()},
+				}),
+				nt.NewParagraphBlock(nt.Paragraph{
+					RichText: []nt.RichText{*nt.NewTextRichText("Six").AnnotateBold()},
+				}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			p := jalapeno.NewParser(goldmark.New(), jalapeno.WithHeadingStrategy(tt.strategy))
+
+			blocks, _, err := p.ParseBlocks([]byte(source))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, blocks)
+		})
+	}
+
+	// Shifting applies only when the single H1 becomes the page title, other documents are clamped
+	shift := jalapeno.NewParser(nil, jalapeno.WithHeadingStrategy(jalapeno.HeadingStrategyShift))
+	title := func(text string) nt.Properties {
+		return nt.Properties{"title": nt.TitleProperty{Title: []nt.RichText{*nt.NewTextRichText(text)}}}
+	}
+
+	t.Run("shift without H1", func(t *testing.T) {
+		result, err := shift.Convert(context.Background(), []byte("## Intro\n\ntext\n\n### Sub"))
+		require.NoError(t, err)
+		assert.Equal(t, title(jalapeno.DefaultTitle), result.Properties)
+		assert.Equal(t, nt.Blocks{
+			heading(2, "Intro"),
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{*nt.NewTextRichText("text")},
+				Children: nt.Blocks{},
+			}),
+			heading(3, "Sub"),
+		}, result.Blocks)
+	})
+
+	t.Run("shift with several H1", func(t *testing.T) {
+		result, err := shift.Convert(context.Background(), []byte("# One\n\n## Two\n\n# Three\n\n#### Four"))
+		require.NoError(t, err)
+		assert.Equal(t, title("One"), result.Properties)
+		assert.Equal(t, nt.Blocks{heading(2, "Two"), heading(1, "Three"), heading(3, "Four")}, result.Blocks)
+	})
+
+	t.Run("shift with title in front matter", func(t *testing.T) {
+		result, err := shift.Convert(context.Background(), []byte("---\ntitle: Doc\n---\n# One\n\n## Two"))
+		require.NoError(t, err)
+		assert.Equal(t, nt.Blocks{heading(1, "One"), heading(2, "Two")}, result.Blocks)
+	})
+
+	t.Run("shift with the title H1", func(t *testing.T) {
+		result, err := shift.Convert(context.Background(), []byte("# One\n\n## Two\n\n### Three"))
+		require.NoError(t, err)
+		assert.Equal(t, title("One"), result.Properties)
+		assert.Equal(t, nt.Blocks{heading(1, "Two"), heading(2, "Three")}, result.Blocks)
+	})
+}
+
+func TestParser_ParseBlocks_HTMLStrategies(t *testing.T) {
+	const source = "<script>alert(1)</script>\n\n<p>Supported</p>"
+
+	supported := nt.NewParagraphBlock(nt.Paragraph{RichText: []nt.RichText{*nt.NewTextRichText("Supported")}})
+
+	tests := []struct {
+		strategy jalapeno.HTMLStrategy
+		expected nt.Blocks
+	}{
+		{
+			strategy: jalapeno.HTMLStrategyText,
+			expected: nt.Blocks{
+				nt.NewParagraphBlock(nt.Paragraph{RichText: []nt.RichText{*nt.NewTextRichText("<script>alert(1)</script>")}}),
+				supported,
+			},
+		},
+		{
+			strategy: jalapeno.HTMLStrategyCode,
+			expected: nt.Blocks{
+				nt.NewCodeBlock(nt.Code{
+					RichText: []nt.RichText{*nt.NewTextRichText("<script>alert(1)</script>")},
+					Language: "html",
+				}),
+				supported,
+			},
+		},
+		{
+			strategy: jalapeno.HTMLStrategySkip,
+			expected: nt.Blocks{supported},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			p := jalapeno.NewParser(nil, jalapeno.WithHTMLStrategy(tt.strategy))
+
+			blocks, _, err := p.ParseBlocks([]byte(source))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, blocks)
+		})
+	}
+}
+
+func TestParser_ParseBlocks_Diagnostics(t *testing.T) {
+	const source = `# Diagnostics
+
+<script>alert("ok")</script>
+
+Текст
+
+<details>
+<summary>More</summary>
+
+<iframe src="demo.html"></iframe>
+
+</details>
+
+<details><summary>Inline</summary><iframe src="inline.html"></iframe></details>`
+
+	expected := jalapeno.Diagnostics{
+		{Kind: "HTMLBlock", Line: 3, Column: 1, Message: "HTML block is not supported, it's kept as raw text"},
+		{Kind: "HTMLBlock", Line: 10, Column: 1, Message: "HTML block is not supported, it's kept as raw text"},
+		// Content of the single-block `<details>` is parsed separately, so it points at the `<details>` itself
+		{Kind: "HTMLBlock", Line: 14, Column: 1, Message: "HTML block is not supported, it's kept as raw text"},
+	}
+
+	t.Run("collected", func(t *testing.T) {
+		blocks, diagnostics, err := jalapeno.NewParser(nil).ParseBlocks([]byte(source))
+		require.NoError(t, err)
+		assert.NotEmpty(t, blocks)
+		assert.ElementsMatch(t, expected, diagnostics)
+	})
+
+	t.Run("chosen HTML strategy is not reported", func(t *testing.T) {
+		_, diagnostics, err := jalapeno.NewParser(nil, jalapeno.WithHTMLStrategy(jalapeno.HTMLStrategySkip)).
+			ParseBlocks([]byte(source))
+		require.NoError(t, err)
+		assert.Empty(t, diagnostics)
+	})
+
+	t.Run("strict mode", func(t *testing.T) {
+		blocks, diagnostics, err := jalapeno.NewParser(nil, jalapeno.WithStrictMode(true)).ParseBlocks([]byte(source))
+		require.Error(t, err)
+		assert.Nil(t, blocks)
+		assert.ElementsMatch(t, expected, diagnostics)
+
+		var diagnosticsErr *jalapeno.DiagnosticsError
+		require.ErrorAs(t, err, &diagnosticsErr)
+		assert.ElementsMatch(t, expected, diagnosticsErr.Diagnostics)
+		assert.Contains(t, err.Error(), "14:1: HTMLBlock: HTML block is not supported")
+	})
+
+	t.Run("HTML image without src", func(t *testing.T) {
+		blocks, diagnostics, err := jalapeno.NewParser(nil).ParseBlocks([]byte(`<p>Logo: <img alt="x"></p>`))
+		require.NoError(t, err)
+		assert.Equal(t, nt.Blocks{
+			nt.NewParagraphBlock(nt.Paragraph{
+				RichText: []nt.RichText{*nt.NewTextRichText("Logo: "), *nt.NewTextRichText("x")},
+			}),
+		}, blocks)
+		assert.Equal(t, jalapeno.Diagnostics{
+			{Kind: "HTMLFragment", Line: 1, Column: 1, Message: "image has no src, its alt text is kept as plain text"},
+		}, diagnostics)
+	})
+
+	t.Run("footnote with a list is not reported", func(t *testing.T) {
+		blocks, diagnostics, err := jalapeno.NewParser(nil, jalapeno.WithStrictMode(true)).
+			ParseBlocks([]byte("Text[^1]\n\n[^1]: - item"))
+		require.NoError(t, err)
+		assert.Empty(t, diagnostics)
+
+		require.Len(t, blocks, 4, "paragraph, divider, heading and the footnote")
+		footnote := blocks[3].(*nt.NumberedListItemBlock)
+		require.Len(t, footnote.NumberedListItem.Children, 1)
+		assert.Equal(t, "item", footnote.NumberedListItem.Children[0].GetRichTextString())
+	})
+
+	t.Run("strict mode without diagnostics", func(t *testing.T) {
+		blocks, diagnostics, err := jalapeno.NewParser(nil, jalapeno.WithStrictMode(true)).ParseBlocks([]byte("# Fine"))
+		require.NoError(t, err)
+		assert.Len(t, blocks, 1)
+		assert.Empty(t, diagnostics)
+	})
+}
+
+func TestParser_ParseBlocks_LongHTMLCode(t *testing.T) {
+	p := jalapeno.NewParser(nil, jalapeno.WithHTMLStrategy(jalapeno.HTMLStrategyCode))
+
+	blocks, _, err := p.ParseBlocks([]byte("<script>\n" + strings.Repeat("x", 2500) + "\n</script>"))
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+
+	richTexts := blocks[0].(*nt.CodeBlock).Code.RichText
+	require.Len(t, richTexts, 2, "HTML code is split into several rich texts")
+	assert.Len(t, richTexts[0].PlainText, 2000)
+}
+
+func TestParser_ParseBlocks_DefaultCodeLanguage(t *testing.T) {
+	p := jalapeno.NewParser(nil, jalapeno.WithDefaultCodeLanguage("shell"))
+
+	blocks, _, err := p.ParseBlocks([]byte("```\nmake\n```\n\n    indented\n\n```go\nx := 1\n```"))
+	require.NoError(t, err)
+
+	languages := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		languages = append(languages, block.(*nt.CodeBlock).Code.Language)
+	}
+	assert.Equal(t, []string{"shell", "shell", "go"}, languages)
+}
+
+func TestParser_ParseBlocks_ToggleHeadings(t *testing.T) {
+	const source = `Intro
+
+## First
+
+Text
+
+### Details
+
+More
+
+## Second
+
+# Top`
+
+	paragraph := func(text string) nt.Block {
+		return nt.NewParagraphBlock(nt.Paragraph{
+			RichText: []nt.RichText{*nt.NewTextRichText(text)},
+			Children: nt.Blocks{},
+		})
+	}
+	heading := func(text string) nt.Heading {
+		return nt.Heading{RichText: []nt.RichText{*nt.NewTextRichText(text)}}
+	}
+
+	p := jalapeno.NewParser(goldmark.New(), jalapeno.WithToggleHeadings(2))
+
+	blocks, _, err := p.ParseBlocks([]byte(source))
+	require.NoError(t, err)
+	assert.Equal(t, nt.Blocks{
+		paragraph("Intro"),
+		nt.NewHeading2Block(nt.Heading{
+			RichText:     []nt.RichText{*nt.NewTextRichText("First")},
+			IsToggleable: true,
+			Children: nt.Blocks{
+				paragraph("Text"),
+				nt.NewHeading3Block(heading("Details")),
+				paragraph("More"),
+			},
+		}),
+		nt.NewHeading2Block(nt.Heading{
+			RichText:     []nt.RichText{*nt.NewTextRichText("Second")},
+			IsToggleable: true,
+			Children:     nt.Blocks{},
+		}),
+		nt.NewHeading1Block(heading("Top")),
+	}, blocks)
+}
+
+func TestRepoLinkResolver(t *testing.T) {
+	resolver := &jalapeno.RepoLinkResolver{
+		DocPath: "docs/guide.md",
+		Pages: map[string]string{
+			"docs/guide.md": "https://www.notion.so/Guide-111",
+			"docs/SETUP.md": "https://www.notion.so/Setup-222",
+			"readme.md":     "https://www.notion.so/Readme-333",
+		},
+		RepoURL: "https://github.com/amberpixels/peppers/blob/main/",
+	}
+
+	tests := map[string]string{
+		"SETUP.md":                   "https://www.notion.so/Setup-222",
+		"./SETUP.md#install":         "https://www.notion.so/Setup-222",
+		"../readme.md":               "https://www.notion.so/Readme-333",
+		"/readme.md":                 "https://www.notion.so/Readme-333",
+		"#usage":                     "https://www.notion.so/Guide-111",
+		"../cmd/pprs/main.go":        "https://github.com/amberpixels/peppers/blob/main/cmd/pprs/main.go",
+		"../go.mod#L3":               "https://github.com/amberpixels/peppers/blob/main/go.mod#L3",
+		"my notes.txt":               "https://github.com/amberpixels/peppers/blob/main/docs/my%20notes.txt",
+		"https://example.com/a.md":   "https://example.com/a.md",
+		"mailto:someone@example.com": "mailto:someone@example.com",
+	}
+	for destination, expected := range tests {
+		assert.Equal(t, expected, resolver.ResolveLink(destination), destination)
+	}
+
+	// Without repository URL links to non-converted files are kept as they are
+	resolver.RepoURL = ""
+	assert.Equal(t, "../go.mod", resolver.ResolveLink("../go.mod"))
+}
+
+func TestParser_ParseBlocks_LinkResolver(t *testing.T) {
+	const source = `See [setup](SETUP.md#install) or <a href="../readme.md">readme</a>.
+
+<p>Read <a href="SETUP.md">the setup</a></p>
+
+<details>
+<summary>More</summary>
+
+[Home](../readme.md)
+
+</details>`
+
+	p := jalapeno.NewParser(goldmark.New(), jalapeno.WithLinkResolver(&jalapeno.RepoLinkResolver{
+		DocPath: "docs/guide.md",
+		Pages: map[string]string{
+			"docs/SETUP.md": "https://www.notion.so/Setup-222",
+			"readme.md":     "https://www.notion.so/Readme-333",
+		},
+	}))
+
+	blocks, _, err := p.ParseBlocks([]byte(source))
+	require.NoError(t, err)
+	require.Len(t, blocks, 3)
+
+	links := func(richTexts []nt.RichText) []string {
+		result := make([]string, 0)
+		for _, rt := range richTexts {
+			if rt.Text != nil && rt.Text.Link != nil {
+				result = append(result, rt.Text.Link.Url)
+			}
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{
+		"https://www.notion.so/Setup-222",
+		"https://www.notion.so/Readme-333",
+	}, links(blocks[0].(*nt.ParagraphBlock).Paragraph.RichText))
+	assert.Equal(t, []string{
+		"https://www.notion.so/Setup-222",
+	}, links(blocks[1].(*nt.ParagraphBlock).Paragraph.RichText))
+
+	toggle := blocks[2].(*nt.ToggleBlock)
+	require.Len(t, toggle.Toggle.Children, 1)
+	assert.Equal(t, []string{
+		"https://www.notion.so/Readme-333",
+	}, links(toggle.Toggle.Children[0].(*nt.ParagraphBlock).Paragraph.RichText))
+}
+
+func TestParser_ParseBlocks_UnresolvedLinks(t *testing.T) {
+	const source = `See [install](#install), <a href="SETUP.md">setup</a> and [site](https://example.com).
+
+<p><a href="#top">Top</a></p>
+
+[![CI](https://example.com/ci.svg)](ci.md)`
+
+	blocks, diagnostics, err := jalapeno.NewParser(nil).ParseBlocks([]byte(source))
+	require.NoError(t, err)
+	require.Len(t, blocks, 3)
+
+	// Notion rejects relative links, so they are flattened into their text
+	paragraph := blocks[0].(*nt.ParagraphBlock).Paragraph
+	assert.Equal(t, "See install, setup and site.", blocks[0].GetRichTextString())
+	for _, rt := range paragraph.RichText {
+		if rt.PlainText == "site" {
+			assert.Equal(t, "https://example.com", rt.Href)
+		} else {
+			assert.Nil(t, rt.Text.Link, "%q is not linked", rt.PlainText)
+		}
+	}
+	assert.Nil(t, blocks[1].(*nt.ParagraphBlock).Paragraph.RichText[0].Text.Link)
+	image := blocks[2].(*nt.ParagraphBlock).Paragraph.Children[0].(*nt.ImageBlock)
+	assert.Nil(t, image.Image.Caption[0].Text.Link)
+
+	messages := make([]string, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		messages = append(messages, d.Message)
+	}
+	assert.ElementsMatch(t, []string{
+		`link "#install" can't be resolved, it's kept as plain text`,
+		`link "SETUP.md" can't be resolved, it's kept as plain text`,
+		`link "#top" can't be resolved, it's kept as plain text`,
+		`link "ci.md" can't be resolved, it's kept as plain text`,
+	}, messages)
+}
+
+func TestConvert(t *testing.T) {
+	t.Run("title from the first heading", func(t *testing.T) {
+		result, err := jalapeno.Convert(context.Background(), []byte("Intro\n\n# Title\n\nText"))
+		require.NoError(t, err)
+
+		assert.Equal(t, nt.Properties{
+			"title": nt.TitleProperty{Title: []nt.RichText{*nt.NewTextRichText("Title")}},
+		}, result.Properties)
+		require.Len(t, result.Blocks, 2)
+		assert.Equal(t, "Intro", result.Blocks[0].GetRichTextString())
+		assert.Equal(t, "Text", result.Blocks[1].GetRichTextString())
+		assert.Nil(t, result.FrontMatter)
+		assert.Empty(t, result.Diagnostics)
+	})
+
+	t.Run("title from the toggle heading", func(t *testing.T) {
+		result, err := jalapeno.Convert(context.Background(), []byte("# Title\n\nIntro\n\n## Sec\n\nBody"),
+			jalapeno.WithToggleHeadings(1),
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, nt.Properties{
+			"title": nt.TitleProperty{Title: []nt.RichText{*nt.NewTextRichText("Title")}},
+		}, result.Properties)
+		require.Len(t, result.Blocks, 3, "content of the title section is moved up to the page")
+		assert.Equal(t, "Intro", result.Blocks[0].GetRichTextString())
+		assert.Equal(t, nt.BlockTypeHeading2, result.Blocks[1].GetType())
+		assert.Equal(t, "Body", result.Blocks[2].GetRichTextString())
+	})
+
+	t.Run("front matter", func(t *testing.T) {
+		result, err := jalapeno.Convert(context.Background(), []byte("---\ntitle: Hello\nicon: 🌶️\n---\n# Heading"),
+			jalapeno.WithHeadingStrategy(jalapeno.HeadingStrategyShift),
+		)
+		require.NoError(t, err)
+
+		emoji := nt.Emoji("🌶️")
+		assert.Equal(t, nt.Properties{
+			"title": nt.TitleProperty{Title: []nt.RichText{*nt.NewTextRichText("Hello")}},
+		}, result.Properties)
+		assert.Equal(t, &nt.Icon{Type: "emoji", Emoji: &emoji}, result.Icon)
+		assert.Nil(t, result.Cover)
+		assert.Equal(t, "Hello", result.FrontMatter["title"])
+		require.Len(t, result.Blocks, 1, "heading is kept as the title comes from the front matter")
+		assert.Equal(t, nt.BlockTypeHeading1, result.Blocks[0].GetType())
+	})
+
+	t.Run("diagnostics", func(t *testing.T) {
+		result, err := jalapeno.Convert(context.Background(), []byte("# Notes\n\nText\n\n<script>run()</script>"))
+		require.NoError(t, err)
+
+		assert.Equal(t, jalapeno.Diagnostics{{
+			Kind: "HTMLBlock", Line: 5, Column: 1,
+			Message: "HTML block is not supported, it's kept as raw text",
+		}}, result.Diagnostics)
+	})
+
+	t.Run("default title", func(t *testing.T) {
+		result, err := jalapeno.Convert(context.Background(), []byte("Text"), jalapeno.WithDefaultTitle("Notes"))
+		require.NoError(t, err)
+		assert.Equal(t, nt.Properties{
+			"title": nt.TitleProperty{Title: []nt.RichText{*nt.NewTextRichText("Notes")}},
+		}, result.Properties)
+
+		result, err = jalapeno.Convert(context.Background(), []byte("Text"))
+		require.NoError(t, err)
+		assert.Equal(t, nt.Properties{
+			"title": nt.TitleProperty{Title: []nt.RichText{*nt.NewTextRichText(jalapeno.DefaultTitle)}},
+		}, result.Properties)
+	})
+
+	t.Run("source ranges", func(t *testing.T) {
+		source := strings.Join([]string{
+			"---",                     // 1
+			"title: Ranges",           // 2
+			"---",                     // 3
+			"## Heading",              // 4
+			"",                        // 5
+			"Paragraph spanning",      // 6
+			"two lines",               // 7
+			"",                        // 8
+			"```go",                   // 9
+			"fmt.Println()",           // 10
+			"```",                     // 11
+			"",                        // 12
+			"---",                     // 13
+			"",                        // 14
+			"- first",                 // 15
+			"  - nested",              // 16
+			"- second",                // 17
+			"",                        // 18
+			"<details>",               // 19
+			"<summary>More</summary>", // 20
+			"Hidden text",             // 21
+			"</details>",              // 22
+		}, "\n")
+
+		result, err := jalapeno.Convert(context.Background(), []byte(source))
+		require.NoError(t, err)
+		require.Len(t, result.Blocks, 7)
+
+		rangeOf := func(block nt.Block) jalapeno.SourceRange {
+			t.Helper()
+			r, ok := result.SourceRanges[block]
+			require.True(t, ok, "no source range of %s block", block.GetType())
+			return r
+		}
+
+		assert.Equal(t, jalapeno.SourceRange{StartLine: 4, EndLine: 4}, rangeOf(result.Blocks[0]), "heading")
+		assert.Equal(t, jalapeno.SourceRange{StartLine: 6, EndLine: 7}, rangeOf(result.Blocks[1]), "paragraph")
+		assert.Equal(t, jalapeno.SourceRange{StartLine: 9, EndLine: 11}, rangeOf(result.Blocks[2]), "code with fences")
+		assert.Equal(t, jalapeno.SourceRange{StartLine: 13, EndLine: 13}, rangeOf(result.Blocks[3]), "divider")
+
+		first := result.Blocks[4].(*nt.BulletedListItemBlock)
+		assert.Equal(t, jalapeno.SourceRange{StartLine: 15, EndLine: 16}, rangeOf(first), "list item with nested one")
+		require.Len(t, first.BulletedListItem.Children, 1)
+		assert.Equal(t, jalapeno.SourceRange{StartLine: 16, EndLine: 16}, rangeOf(first.BulletedListItem.Children[0]),
+			"nested list item")
+		assert.Equal(t, jalapeno.SourceRange{StartLine: 17, EndLine: 17}, rangeOf(result.Blocks[5]), "second list item")
+
+		toggle := result.Blocks[6].(*nt.ToggleBlock)
+		assert.Equal(t, jalapeno.SourceRange{StartLine: 19, EndLine: 22}, rangeOf(toggle), "details")
+		require.Len(t, toggle.Toggle.Children, 1)
+		assert.Equal(t, jalapeno.SourceRange{StartLine: 19, EndLine: 22}, rangeOf(toggle.Toggle.Children[0]),
+			"details content points at the whole details block")
+	})
+
+	t.Run("source ranges of dividers after tables", func(t *testing.T) {
+		source := strings.Join([]string{
+			"# Title",       // 1
+			"",              // 2
+			"| a | b |",     // 3
+			"| --- | --- |", // 4
+			"| c | d |",     // 5
+			"",              // 6
+			"---",           // 7
+		}, "\n")
+
+		result, err := jalapeno.Convert(context.Background(), []byte(source))
+		require.NoError(t, err)
+		require.Len(t, result.Blocks, 2)
+
+		assert.Equal(t, jalapeno.SourceRange{StartLine: 7, EndLine: 7}, result.SourceRanges[result.Blocks[1]], "divider")
+		for block, r := range result.SourceRanges {
+			assert.NotEqual(t, nt.BlockTypeHeading1, block.GetType(), "range %v of the title heading is kept", r)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := jalapeno.Convert(ctx, []byte("# Title"))
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+// kindAdmonition is a kind of custom nodes made by admonitionTransformer
+var kindAdmonition = mdast.NewNodeKind("Admonition")
+
+type admonition struct {
+	mdast.BaseBlock
+}
+
+func (n *admonition) Kind() mdast.NodeKind { return kindAdmonition }
+
+func (n *admonition) Dump(source []byte, level int) { mdast.DumpHelper(n, source, level, nil, nil) }
+
+// admonitionTransformer stands for a custom goldmark extension: it replaces blockquotes with admonitions
+type admonitionTransformer struct{}
+
+func (admonitionTransformer) Transform(doc *mdast.Document, _ text.Reader, _ parser.Context) {
+	for child := doc.FirstChild(); child != nil; child = child.NextSibling() {
+		if child.Kind() != mdast.KindBlockquote {
+			continue
+		}
+		node := &admonition{}
+		for grandChild := child.FirstChild(); grandChild != nil; grandChild = child.FirstChild() {
+			node.AppendChild(node, grandChild)
+		}
+		doc.ReplaceChild(doc, child, node)
+		child = node
+	}
+}
+
+func TestParser_ParseBlocks_Handlers(t *testing.T) {
+	md := goldmark.New(goldmark.WithParserOptions(
+		parser.WithASTTransformers(util.Prioritized(admonitionTransformer{}, 100)),
+	))
+
+	emojis := map[string]string{":tada:": "🎉"}
+
+	p := jalapeno.NewParser(md,
+		// Custom node
+		jalapeno.WithBlockHandler(kindAdmonition, func(p *jalapeno.Parser, node mdast.Node) jalapeno.NtBlockBuilders {
+			children := make(jalapeno.NtBlockBuilders, 0)
+			for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+				children = append(children, p.ToBlocks(child)...)
+			}
+			return jalapeno.NtBlockBuilders{jalapeno.NewNtBlockBuilder(func(source []byte) nt.Block {
+				return nt.NewCalloutBlock(nt.Callout{
+					RichText: []nt.RichText{*nt.NewTextRichText("Admonition")},
+					Children: children.Build(source),
+				})
+			})}
+		}),
+		// Overridden built-in nodes
+		jalapeno.WithBlockHandler(mdast.KindThematicBreak, func(_ *jalapeno.Parser, _ mdast.Node) jalapeno.NtBlockBuilders {
+			return jalapeno.NtBlockBuilders{jalapeno.NewNtBlockBuilder(func(_ []byte) nt.Block {
+				return nt.NewBreadcrumbBlock()
+			})}
+		}),
+		jalapeno.WithRichTextHandler(mdast.KindCodeSpan, func(_ *jalapeno.Parser, node mdast.Node) jalapeno.NtRichTextBuilders {
+			return jalapeno.NtRichTextBuilders{jalapeno.NewNtRichTextBuilder(func(source []byte) *nt.RichText {
+				code := string(node.FirstChild().(*mdast.Text).Value(source)) // nolint:errcheck
+				if emoji, ok := emojis[code]; ok {
+					return nt.NewTextRichText(emoji)
+				}
+				return nt.NewTextRichText(code).AnnotateCode()
+			})}
+		}),
+		jalapeno.WithBlockHandler(mdast.KindHeading, func(_ *jalapeno.Parser, node mdast.Node) jalapeno.NtBlockBuilders {
+			if node.(*mdast.Heading).Level == 1 { // nolint:errcheck
+				return jalapeno.NtBlockBuilders{} // H1 is dropped
+			}
+			return nil // built-in conversion
+		}),
+	)
+
+	blocks, _, err := p.ParseBlocks([]byte("# Title\n\n> Note with `:tada:` and `code`\n\n---\n\n## Section"))
+	require.NoError(t, err)
+
+	assert.Equal(t, nt.Blocks{
+		nt.NewCalloutBlock(nt.Callout{
+			RichText: []nt.RichText{*nt.NewTextRichText("Admonition")},
+			Children: nt.Blocks{
+				nt.NewParagraphBlock(nt.Paragraph{
+					RichText: []nt.RichText{
+						*nt.NewTextRichText("Note with "),
+						*nt.NewTextRichText("🎉"),
+						*nt.NewTextRichText(" and "),
+						*nt.NewTextRichText("code").AnnotateCode(),
+					},
+					Children: nt.Blocks{},
+				}),
+			},
+		}),
+		nt.NewBreadcrumbBlock(),
+		nt.NewHeading2Block(nt.Heading{RichText: []nt.RichText{*nt.NewTextRichText("Section")}}),
+	}, blocks)
+}
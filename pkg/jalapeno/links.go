@@ -0,0 +1,138 @@
+package jalapeno
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	mdast "github.com/yuin/goldmark/ast"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// LinkResolver rewrites destinations of links found in the document
+// (e.g. relative links to other Markdown files that are meaningless in Notion)
+type LinkResolver interface {
+	// ResolveLink returns the new destination for the given one (the given one is returned if it's kept as is)
+	ResolveLink(destination string) string
+}
+
+// LinkResolverFunc is a function implementing LinkResolver
+type LinkResolverFunc func(destination string) string
+
+// ResolveLink implements LinkResolver.ResolveLink
+func (f LinkResolverFunc) ResolveLink(destination string) string {
+	return f(destination)
+}
+
+// RepoLinkResolver resolves relative links of a document that is a part of a repository (or any directory tree):
+// links to other converted Markdown files point at their Notion pages, links to the rest of files point at the repository browse URL
+// Heading anchors are not supported: IDs of Notion blocks are unknown before the pages are published,
+// so links to headings of converted files (e.g. SETUP.md#install or #usage) point at the pages themselves
+type RepoLinkResolver struct {
+	// DocPath is a slash-separated path of the converted document relative to the repository root
+	DocPath string
+
+	// Pages maps paths of converted Markdown files (relative to the repository root) to URLs of their Notion pages
+	Pages map[string]string
+
+	// RepoURL is an URL browsing the repository root (e.g. https://github.com/owner/repo/blob/main)
+	// If empty, links to non-converted files are kept as is
+	RepoURL string
+}
+
+// ResolveLink implements LinkResolver.ResolveLink
+func (r *RepoLinkResolver) ResolveLink(destination string) string {
+	u, err := url.Parse(destination)
+	if err != nil || u.Scheme != "" || u.Host != "" || (u.Path == "" && u.Fragment == "") {
+		return destination
+	}
+
+	target := r.DocPath
+	if strings.HasPrefix(u.Path, "/") {
+		target = path.Clean(strings.TrimPrefix(u.Path, "/"))
+	} else if u.Path != "" {
+		target = path.Join(path.Dir(r.DocPath), u.Path)
+	}
+
+	if pageURL, ok := r.Pages[target]; ok {
+		return pageURL
+	}
+
+	if r.RepoURL == "" || u.Path == "" {
+		return destination
+	}
+
+	resolved := strings.TrimSuffix(r.RepoURL, "/") + "/" + (&url.URL{Path: target}).EscapedPath()
+	if u.Fragment != "" {
+		resolved += "#" + u.EscapedFragment()
+	}
+	return resolved
+}
+
+// resolveLinks rewrites destinations of all links in the given tree via the link resolver (if it's set)
+// Notion accepts absolute URLs only, so links that are still relative are flattened into their text and reported
+func (p *Parser) resolveLinks(tree mdast.Node) {
+	unresolved := make([]*mdast.Link, 0)
+	_ = mdast.Walk(tree, func(node mdast.Node, entering bool) (mdast.WalkStatus, error) { //nolint:errcheck
+		if !entering {
+			return mdast.WalkContinue, nil
+		}
+
+		switch v := node.(type) {
+		case *mdast.Link:
+			v.Destination = []byte(p.resolveLink(node, string(v.Destination)))
+			if len(v.Destination) == 0 {
+				unresolved = append(unresolved, v)
+			}
+		case *InlineHTML:
+			if v.Href != "" {
+				v.Href = p.resolveLink(node, v.Href)
+			}
+		case *HTMLFragment:
+			for _, n := range v.Nodes {
+				p.resolveHTMLLinks(node, n)
+			}
+		}
+		return mdast.WalkContinue, nil
+	})
+
+	for _, link := range unresolved {
+		parent := link.Parent()
+		for child := link.FirstChild(); child != nil; {
+			next := child.NextSibling()
+			parent.InsertBefore(parent, link, child)
+			child = next
+		}
+		parent.RemoveChild(parent, link)
+	}
+}
+
+// resolveLink returns the resolved destination of the link found in the given node
+// or an empty one (the link is reported then) if it's not an absolute URL
+func (p *Parser) resolveLink(node mdast.Node, destination string) string {
+	resolved := destination
+	if p.linkResolver != nil {
+		resolved = p.linkResolver.ResolveLink(destination)
+	}
+
+	if u, err := url.Parse(resolved); err != nil || u.Scheme == "" {
+		p.report(node, "link %q can't be resolved, it's kept as plain text", destination)
+		return ""
+	}
+	return resolved
+}
+
+// resolveHTMLLinks rewrites hrefs of all `<a>` elements in the given HTML tree (placed in the given node)
+func (p *Parser) resolveHTMLLinks(node mdast.Node, n *html.Node) {
+	if n.Type == html.ElementNode && n.DataAtom == atom.A {
+		for i, attr := range n.Attr {
+			if attr.Key == "href" {
+				n.Attr[i].Val = p.resolveLink(node, attr.Val)
+			}
+		}
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		p.resolveHTMLLinks(node, child)
+	}
+}
@@ -0,0 +1,151 @@
+package jalapeno
+
+import mdast "github.com/yuin/goldmark/ast"
+
+// ParserOption is a functional option that configures the Parser
+type ParserOption func(*Parser)
+
+// FootnoteStyle defines how footnote definitions are rendered in Notion
+// Whatever the style, footnote references are plain superscript markers (e.g. ¹) that are not linked to their definitions:
+// IDs of Notion blocks to link to are unknown before the page is published
+type FootnoteStyle string
+
+const (
+	// FootnoteStyleSection renders all footnotes as a numbered list under a "Footnotes" heading
+	FootnoteStyleSection FootnoteStyle = "section"
+	// FootnoteStyleCallout renders each footnote as a separate callout block
+	FootnoteStyleCallout FootnoteStyle = "callout"
+	// FootnoteStyleToggle renders each footnote as a separate toggle block
+	FootnoteStyleToggle FootnoteStyle = "toggle"
+)
+
+// WithFootnoteStyle sets the way footnote definitions are rendered at the end of the page
+// (references stay unlinked markers, see FootnoteStyle)
+func WithFootnoteStyle(style FootnoteStyle) ParserOption {
+	return func(p *Parser) {
+		p.footnoteStyle = style
+	}
+}
+
+// DefinitionListStyle defines how terms of definition lists are rendered in Notion
+type DefinitionListStyle string
+
+const (
+	// DefinitionListStyleParagraph renders each term as a bold paragraph with its descriptions as nested children
+	DefinitionListStyleParagraph DefinitionListStyle = "paragraph"
+	// DefinitionListStyleToggle renders each term as a toggle with its descriptions hidden inside
+	DefinitionListStyleToggle DefinitionListStyle = "toggle"
+)
+
+// WithDefinitionListStyle sets the way definition lists are rendered
+func WithDefinitionListStyle(style DefinitionListStyle) ParserOption {
+	return func(p *Parser) {
+		p.definitionListStyle = style
+	}
+}
+
+// HeadingStrategy defines how six levels of Markdown headings are mapped into three levels of Notion headings
+type HeadingStrategy string
+
+const (
+	// HeadingStrategyClamp keeps H1-H3 as they are and renders H4-H6 as Notion's Heading 3
+	HeadingStrategyClamp HeadingStrategy = "clamp"
+	// HeadingStrategyShift moves all headings one level up (H2 becomes Heading 1, H3 becomes Heading 2, etc.)
+	// when the single H1 of the document becomes the page title (so H1 is kept as it is), H5-H6 are clamped
+	// Documents without H1, with several ones or with the title in the front matter are clamped instead
+	HeadingStrategyShift HeadingStrategy = "shift"
+	// HeadingStrategyParagraph keeps H1-H3 as they are and renders H4-H6 as bold paragraphs
+	HeadingStrategyParagraph HeadingStrategy = "paragraph"
+)
+
+// WithHeadingStrategy sets the way Markdown headings are mapped into Notion headings
+func WithHeadingStrategy(strategy HeadingStrategy) ParserOption {
+	return func(p *Parser) {
+		p.headingStrategy = strategy
+	}
+}
+
+// WithToggleHeadings makes every heading of the given Markdown level (1-6) a toggleable heading
+// with the content of its section nested inside. Zero level disables toggle headings
+func WithToggleHeadings(level int) ParserOption {
+	return func(p *Parser) {
+		p.toggleHeadingLevel = level
+	}
+}
+
+// WithLinkResolver sets the resolver that rewrites destinations of all links in the document
+// (e.g. see RepoLinkResolver for relative links between Markdown files of a repository)
+func WithLinkResolver(resolver LinkResolver) ParserOption {
+	return func(p *Parser) {
+		p.linkResolver = resolver
+	}
+}
+
+// WithDefaultTitle sets the page title used by Convert when the document has neither a front matter title
+// nor H1 heading (DefaultTitle by default)
+func WithDefaultTitle(title string) ParserOption {
+	return func(p *Parser) {
+		p.defaultTitle = title
+	}
+}
+
+// WithDefaultCodeLanguage sets the language of code blocks that have no language specified ("plain text" by default)
+// It must be one of languages supported by Notion
+func WithDefaultCodeLanguage(language string) ParserOption {
+	return func(p *Parser) {
+		p.defaultCodeLanguage = language
+	}
+}
+
+// HTMLStrategy defines how HTML blocks that can't be converted into Notion blocks are rendered
+// Note: supported HTML (`<details>`, paragraphs, images, tables, inline formatting tags) is always converted
+type HTMLStrategy string
+
+const (
+	// HTMLStrategyText keeps unsupported HTML blocks as paragraphs of raw HTML text
+	HTMLStrategyText HTMLStrategy = "text"
+	// HTMLStrategyCode renders unsupported HTML blocks as HTML code blocks
+	HTMLStrategyCode HTMLStrategy = "code"
+	// HTMLStrategySkip drops unsupported HTML blocks
+	HTMLStrategySkip HTMLStrategy = "skip"
+)
+
+// WithHTMLStrategy sets the way unsupported HTML blocks are rendered
+func WithHTMLStrategy(strategy HTMLStrategy) ParserOption {
+	return func(p *Parser) {
+		p.htmlStrategy = strategy
+	}
+}
+
+// BlockHandler converts the given Markdown AST node into Notion blocks
+// Nested nodes can be converted with the given parser (see Parser.ToBlocks and Parser.ExtractRichTexts)
+// Returning nil falls back to the built-in conversion of the node
+type BlockHandler func(p *Parser, node mdast.Node) NtBlockBuilders
+
+// RichTextHandler converts the given inline Markdown AST node into Notion rich texts
+// Returning nil falls back to the built-in conversion of the node
+type RichTextHandler func(p *Parser, node mdast.Node) NtRichTextBuilders
+
+// WithBlockHandler registers the handler converting Markdown AST nodes of the given kind into Notion blocks
+// It's meant for nodes of custom goldmark extensions, or to override the built-in conversion
+func WithBlockHandler(kind mdast.NodeKind, handler BlockHandler) ParserOption {
+	return func(p *Parser) {
+		p.blockHandlers[kind] = handler
+	}
+}
+
+// WithRichTextHandler registers the handler converting inline Markdown AST nodes of the given kind into rich texts
+// Nodes of the kind are kept inside rich texts of their parent blocks (e.g. paragraphs, list items, headings)
+func WithRichTextHandler(kind mdast.NodeKind, handler RichTextHandler) ParserOption {
+	return func(p *Parser) {
+		p.richTextHandlers[kind] = handler
+	}
+}
+
+// WithStrictMode makes the conversion fail with DiagnosticsError if the document has any diagnostics
+// (e.g. to block publishing of pages that are not converted properly)
+func WithStrictMode(strict bool) ParserOption {
+	return func(p *Parser) {
+		p.strict = strict
+	}
+}
@@ -0,0 +1,169 @@
+package jalapeno
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	nt "github.com/jomei/notionapi"
+	mdast "github.com/yuin/goldmark/ast"
+)
+
+// SourceRange is the range of lines of the Markdown source a block was converted from (1-based, inclusive)
+type SourceRange struct {
+	StartLine int
+	EndLine   int
+}
+
+// bindSource binds the given builder to the node it's converted from (if it's not bound yet)
+// and makes it record the source range of the built block
+func (p *Parser) bindSource(builder *NtBlockBuilder, node mdast.Node) {
+	if builder == nil || builder.node != nil {
+		return
+	}
+	builder.node = node
+	builder.DecorateWith(p.recordSource(node))
+}
+
+// recordSource returns a block decorator that remembers the source range of the given node for built blocks
+// Blocks of nested documents (e.g. `<details>` content) get the range of their origin
+func (p *Parser) recordSource(node mdast.Node) func(source []byte, block nt.Block) {
+	return func(source []byte, block nt.Block) {
+		if block == nil || p.sourceRanges == nil {
+			return
+		}
+		positioned := node
+		if p.origin != nil {
+			positioned, source = p.origin, p.source
+		}
+
+		start, stop, ok := nodeRange(positioned, source)
+		if !ok {
+			return
+		}
+		startLine, _ := lineColumn(source, start)
+		endLine, _ := lineColumn(source, max(start, stop-1))
+		p.sourceRanges[block] = SourceRange{StartLine: startLine, EndLine: endLine}
+	}
+}
+
+// nodeRange returns the byte range of the given node in the source
+// Nodes having no segments (e.g. thematic breaks) are placed at the first non-blank line after the last line of their previous sibling,
+// or are given the range of their parent if there is nothing there
+func nodeRange(node mdast.Node, source []byte) (start, stop int, ok bool) {
+	if node == nil || node.Kind() == mdast.KindDocument {
+		return 0, 0, false
+	}
+	if start, stop, ok := positionedRange(node, source); ok {
+		return start, stop, true
+	}
+
+	from, limit := 0, len(source)
+	if parentStart, _, ok := nodeRange(node.Parent(), source); ok {
+		from = parentStart
+	}
+	for prev := node.PreviousSibling(); prev != nil; prev = prev.PreviousSibling() {
+		if _, prevStop, ok := positionedRange(prev, source); ok {
+			// Segments of some blocks (e.g. tables) don't reach the end of their last line
+			from = lineEnd(source, max(prevStop-1, 0))
+			break
+		}
+	}
+	for next := node.NextSibling(); next != nil; next = next.NextSibling() {
+		if nextStart, _, ok := positionedRange(next, source); ok {
+			limit = nextStart
+			break
+		}
+	}
+
+	limit = max(limit, from)
+	start = from + len(source[from:limit]) - len(bytes.TrimLeft(source[from:limit], " \t\r\n"))
+	if start >= limit {
+		return nodeRange(node.Parent(), source)
+	}
+	return start, lineEnd(source, start), true
+}
+
+// positionedRange returns the byte range of the given node taken from segments of the node and its descendants
+func positionedRange(node mdast.Node, source []byte) (start, stop int, ok bool) {
+	if start, stop, ok = segmentsRange(node); ok {
+		if code, isCode := node.(*mdast.FencedCodeBlock); isCode {
+			start, stop = fencesRange(code, source, start, stop)
+		}
+	}
+	return start, stop, ok
+}
+
+// segmentsRange returns the range covering segments of the given node and all its descendants
+func segmentsRange(node mdast.Node) (start, stop int, ok bool) {
+	if start, stop, ok = ownSegments(node); !ok {
+		start, stop = -1, -1
+	}
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		childStart, childStop, childOK := segmentsRange(child)
+		if !childOK {
+			continue
+		}
+		if start < 0 || childStart < start {
+			start = childStart
+		}
+		stop = max(stop, childStop)
+	}
+	return start, stop, start >= 0
+}
+
+// ownSegments returns the range of segments of the given node itself
+func ownSegments(node mdast.Node) (start, stop int, ok bool) {
+	switch v := node.(type) {
+	case *mdast.Text:
+		return v.Segment.Start, v.Segment.Stop, true
+	case *mdast.RawHTML:
+		if v.Segments.Len() > 0 {
+			return v.Segments.At(0).Start, v.Segments.At(v.Segments.Len() - 1).Stop, true
+		}
+	default:
+		if node.Type() == mdast.TypeBlock && node.Lines().Len() > 0 {
+			return node.Lines().At(0).Start, node.Lines().At(node.Lines().Len() - 1).Stop, true
+		}
+	}
+	return 0, 0, false
+}
+
+// fencesRange extends the range of code lines of the fenced code block to its opening and closing fences
+func fencesRange(code *mdast.FencedCodeBlock, source []byte, start, stop int) (int, int) {
+	if code.Info != nil {
+		start = min(start, lineStart(source, code.Info.Segment.Start))
+	} else if start > 0 {
+		start = lineStart(source, start-1)
+	}
+
+	next := stop
+	if next > 0 && source[next-1] != '\n' {
+		next = lineEnd(source, next) + 1
+	}
+	if next < len(source) {
+		line := bytes.TrimSpace(source[next:lineEnd(source, next)])
+		if bytes.HasPrefix(line, []byte("```")) || bytes.HasPrefix(line, []byte("~~~")) {
+			stop = lineEnd(source, next)
+		}
+	}
+	return start, stop
+}
+
+// lineStart returns the offset of the beginning of the line containing the given offset
+func lineStart(source []byte, offset int) int {
+	return bytes.LastIndexByte(source[:offset], '\n') + 1
+}
+
+// lineEnd returns the offset of the end of the line containing the given offset (its new line excluded)
+func lineEnd(source []byte, offset int) int {
+	if end := bytes.IndexByte(source[offset:], '\n'); end >= 0 {
+		return offset + end
+	}
+	return len(source)
+}
+
+// lineColumn converts the given offset in the source into 1-based line and column (counted in characters)
+func lineColumn(source []byte, offset int) (line, column int) {
+	offset = min(offset, len(source))
+	return bytes.Count(source[:offset], []byte("\n")) + 1, utf8.RuneCount(source[lineStart(source, offset):offset]) + 1
+}